@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ActionKind - вид действия пользователя над моделью
+type ActionKind string
+
+const (
+	// ActionCreate создает процесс с заданными параметрами
+	ActionCreate ActionKind = "create"
+	// ActionCreateRandom создает Value случайных процессов
+	ActionCreateRandom ActionKind = "create_random"
+	// ActionBlock блокирует процесс до разблокировки
+	ActionBlock ActionKind = "block"
+	// ActionUnblock разблокирует процесс
+	ActionUnblock ActionKind = "unblock"
+	// ActionSignal отправляет процессу сигнал Value
+	ActionSignal ActionKind = "signal"
+	// ActionPriority задает процессу приоритет Value
+	ActionPriority ActionKind = "priority"
+	// ActionQuantum задает процессу квант времени Value
+	ActionQuantum ActionKind = "quantum"
+	// ActionAffinity переключает привязку процесса между всеми процессорами и последним использованным
+	ActionAffinity ActionKind = "affinity"
+	// ActionConfig задает параметру конфигурации Key значение Value
+	ActionConfig ActionKind = "config"
+)
+
+// Action - действие пользователя над моделью, выполняемое между тактами
+// Действия записываются в сеанс вместе с тактом, поэтому ссылаются на процессы по PID
+type Action struct {
+	Tick  int          `json:"tick"`
+	Kind  ActionKind   `json:"kind"`
+	PID   int          `json:"pid,omitempty"`
+	Value int          `json:"value,omitempty"`
+	Key   string       `json:"key,omitempty"`
+	Spec  *ProcessSpec `json:"spec,omitempty"`
+}
+
+// find возвращает процесс таблицы с заданным PID или nil
+func (pt *ProcessTable) find(pid int) *Process {
+	for _, p := range pt.table {
+		if p.PID == pid {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Apply выполняет действие над моделью
+func (a Action) Apply() error {
+	pt := GetProcessTable()
+
+	switch a.Kind {
+	case ActionCreate:
+		if a.Spec == nil {
+			return errors.New("не заданы параметры процесса")
+		}
+		return pt.CreateProcess(*a.Spec)
+	case ActionCreateRandom:
+		for i := 0; i < a.Value; i++ {
+			pt.AddProcess()
+		}
+		return nil
+	case ActionConfig:
+		return setConfigValue(a.Key, a.Value)
+	}
+
+	p := pt.find(a.PID)
+	if p == nil {
+		return fmt.Errorf("процесс %d не найден", a.PID)
+	}
+
+	switch a.Kind {
+	case ActionBlock:
+		// Блокировать можно только пользовательский процесс, блокировка вручную длится до разблокировки
+		if p.GID == 1 {
+			return errors.New("процесс init не блокируется")
+		}
+		p.IOWaitRemains = 0
+		p.SetState(Blocking)
+		GetEventLog().Log(EventBlocked, p.PID, -1, "вручную")
+	case ActionUnblock:
+		// Если процесс блокировался, то он добавляется в очередь следующим для исполнения
+		if p.State != Blocking {
+			return fmt.Errorf("процесс %d не заблокирован", p.PID)
+		}
+		p.IOWaitRemains = 0
+		p.SetState(Readiness)
+		GetEventLog().Log(EventUnblocked, p.PID, -1, "вручную")
+	case ActionSignal:
+		return pt.SendSignal(p, Signal(a.Value))
+	case ActionPriority:
+		return pt.SetPriority(p, a.Value)
+	case ActionQuantum:
+		return pt.SetQuantum(p, a.Value)
+	case ActionAffinity:
+		return p.toggleAffinity()
+	default:
+		return fmt.Errorf("неизвестное действие %q", a.Kind)
+	}
+
+	return nil
+}
+
+// toggleAffinity переключает привязку процесса между всеми процессорами и последним использованным
+func (p *Process) toggleAffinity() error {
+	if p.GID == 1 {
+		return errors.New("привязка процесса init не меняется")
+	}
+
+	if p.Affinity == allCPUsMask() && p.LastCPU != -1 {
+		p.Affinity = 1 << uint(p.LastCPU)
+		if GetConfig().PerCPUQueues {
+			p.CPU = p.LastCPU
+		}
+	} else {
+		p.Affinity = allCPUsMask()
+	}
+
+	return nil
+}
@@ -1,6 +1,10 @@
 package main
 
-import "github.com/nsf/termbox-go"
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
 
 // Инициализирует библиотеку псевдографики, если инициализация прошла успешно,
 // включает режим ввода с мыши и клавиши Escape.
@@ -13,23 +17,13 @@ func initTermbox() error {
 	return nil
 }
 
-// Опрашивает события библиотеки псевдографики и выполняет переданные замыкания
-func pollEvents(mouseLeftAction func(*termbox.Event), mouseAction func(*termbox.Event),
-	KeyEscAction func(*termbox.Event), keyboardAction func(*termbox.Event)) {
-	switch ev := termbox.PollEvent(); ev.Type {
-	case termbox.EventMouse:
-		if ev.Key == termbox.MouseLeft {
-			mouseLeftAction(&ev)
-		} else {
-			mouseAction(&ev)
-		}
-	case termbox.EventKey:
-		if ev.Key == termbox.KeyEsc {
-			KeyEscAction(&ev)
-		} else {
-			keyboardAction(&ev)
+// Запускает генератор тактов модели: по таймеру прерывает ожидание событий библиотеки псевдографики
+func startTicker(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			termbox.Interrupt()
 		}
-	}
+	}()
 }
 
 // Очищает экран заданным цветом, выполняет конвеер, переданный в замыкании и выводит на экран
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// bankerScreen - экран алгоритма банкира с матрицами Available, Max, Allocation и Need
+type bankerScreen struct{}
+
+// Title возвращает название экрана
+func (s *bankerScreen) Title() string {
+	return "Банкир"
+}
+
+// Enter вызывается при переходе на экран
+func (s *bankerScreen) Enter() {}
+
+// Leave вызывается при уходе с экрана
+func (s *bankerScreen) Leave() {}
+
+// HandleEvent не обрабатывает события: экран только отображает состояние
+func (s *bankerScreen) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// Draw отображает матрицы алгоритма банкира
+func (s *bankerScreen) Draw() {
+	theme := uitools.CurrentTheme
+
+	uitools.Print(0, 1, theme.Normal.Fg, theme.Normal.Bg, "Алгоритм банкира")
+	GetResourceManager().Draw(0, 3)
+
+	drawStatusBar()
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"operating-systems/processes/uitools"
+	"sync"
+)
+
+// ResourceManager - представление распределителя ресурсов, предотвращающего тупики алгоритмом банкира
+type ResourceManager struct {
+	Total     []int
+	Available []int
+	// DeferredCount - число отложенных запросов, которые привели бы к небезопасному состоянию
+	DeferredCount int
+}
+
+// Need возвращает остаточную потребность процесса: Max - Allocation
+func (p *Process) Need() []int {
+	need := make([]int, len(p.MaxClaim))
+	for r := range need {
+		need[r] = p.MaxClaim[r] - p.Allocation[r]
+	}
+
+	return need
+}
+
+// GenerateRequest формирует случайный запрос ресурсов в пределах остаточной потребности, если его еще нет
+func (p *Process) GenerateRequest() {
+	if p.Request != nil {
+		return
+	}
+
+	need := p.Need()
+	request := make([]int, len(need))
+	isEmpty := true
+	for r := range need {
+		if need[r] > 0 {
+			request[r] = random.Intn(need[r] + 1)
+		}
+		if request[r] > 0 {
+			isEmpty = false
+		}
+	}
+
+	if !isEmpty {
+		p.Request = request
+	}
+}
+
+// IsSafe проверяет, существует ли последовательность, в которой все процессы таблицы могут завершиться
+func (rm *ResourceManager) IsSafe() bool {
+	pt := GetProcessTable()
+
+	work := make([]int, len(rm.Available))
+	copy(work, rm.Available)
+	finish := make([]bool, len(pt.table))
+
+	for {
+		progress := false
+		for i, p := range pt.table {
+			if finish[i] || p.MaxClaim == nil {
+				finish[i] = true
+				continue
+			}
+
+			if lessOrEqual(p.Need(), work) {
+				for r := range work {
+					work[r] += p.Allocation[r]
+				}
+				finish[i] = true
+				progress = true
+			}
+		}
+
+		if !progress {
+			break
+		}
+	}
+
+	for _, f := range finish {
+		if !f {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Request пытается выделить процессу запрошенные им ресурсы
+// Возвращает false, если запрос должен быть отложен
+func (rm *ResourceManager) Request(process *Process) bool {
+	if process.Request == nil {
+		return true
+	}
+
+	// Ресурсов недостаточно - процесс ждет их освобождения в любом режиме
+	if !lessOrEqual(process.Request, rm.Available) {
+		process.Deferred = true
+		return false
+	}
+
+	// Пробное выделение ресурсов
+	for r := range process.Request {
+		rm.Available[r] -= process.Request[r]
+		process.Allocation[r] += process.Request[r]
+	}
+
+	// В режиме банкира небезопасное выделение откатывается, запрос откладывается
+	if GetConfig().BankerMode && !rm.IsSafe() {
+		for r := range process.Request {
+			rm.Available[r] += process.Request[r]
+			process.Allocation[r] -= process.Request[r]
+		}
+		process.Deferred = true
+		rm.DeferredCount++
+		return false
+	}
+
+	process.Request = nil
+	process.Deferred = false
+	return true
+}
+
+// Release возвращает все удерживаемые процессом ресурсы
+func (rm *ResourceManager) Release(process *Process) {
+	for r := range process.Allocation {
+		rm.Available[r] += process.Allocation[r]
+		process.Allocation[r] = 0
+	}
+	process.Request = nil
+	process.Deferred = false
+}
+
+// Draw отображает векторы Available и матрицы Max, Allocation, Need в заданной области
+func (rm *ResourceManager) Draw(x, y int) {
+	pt := GetProcessTable()
+	theme := uitools.CurrentTheme
+
+	mode := "выключен"
+	if GetConfig().BankerMode {
+		mode = "включен"
+	}
+	safety := "безопасное"
+	if !rm.IsSafe() {
+		safety = "небезопасное"
+	}
+
+	uitools.Printf(x, y, theme.Normal.Fg, theme.Normal.Bg, "Режим банкира: %s  Состояние: %s  Отложено запросов: %d", mode, safety, rm.DeferredCount)
+	uitools.Printf(x, y+1, theme.Normal.Fg, theme.Normal.Bg, "Всего: %s  Available: %s", formatVector(rm.Total), formatVector(rm.Available))
+	uitools.Printf(x, y+3, theme.Normal.Fg, theme.Normal.Bg, "%5s %-16s %-16s %-16s %-16s", "PID", "Max", "Allocation", "Need", "Запрос")
+
+	row := 0
+	for _, p := range pt.table {
+		if p.MaxClaim == nil {
+			continue
+		}
+
+		style := theme.Normal
+		if p.Deferred {
+			style = theme.Warning
+		}
+		uitools.Printf(x, y+4+row, style.Fg, style.Bg, "%5d %-16s %-16s %-16s %-16s", p.PID,
+			formatVector(p.MaxClaim), formatVector(p.Allocation), formatVector(p.Need()), formatVector(p.Request))
+		row++
+	}
+}
+
+// lessOrEqual проверяет покомпонентное неравенство a <= b
+func lessOrEqual(a, b []int) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatVector переводит вектор ресурсов в строку
+func formatVector(v []int) string {
+	if v == nil {
+		return "-"
+	}
+
+	return fmt.Sprint(v)
+}
+
+var bankerOnce sync.Once
+var resourceManagerInstance *ResourceManager
+
+// GetResourceManager предоставляет единственный экземпляр распределителя ресурсов
+func GetResourceManager() *ResourceManager {
+	bankerOnce.Do(func() {
+		resources := GetConfig().Resources
+		resourceManagerInstance = &ResourceManager{Total: make([]int, len(resources)), Available: make([]int, len(resources))}
+		copy(resourceManagerInstance.Total, resources)
+		copy(resourceManagerInstance.Available, resources)
+	})
+
+	return resourceManagerInstance
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// bankerProcess - процесс задачи алгоритма банкира: выделенные ресурсы и максимальная потребность
+type bankerProcess struct {
+	allocation []int
+	maxClaim   []int
+}
+
+// textbookProcesses - классический пример из пяти процессов и трех типов ресурсов {10, 5, 7}
+var textbookProcesses = []bankerProcess{
+	{[]int{0, 1, 0}, []int{7, 5, 3}},
+	{[]int{2, 0, 0}, []int{3, 2, 2}},
+	{[]int{3, 0, 2}, []int{9, 0, 2}},
+	{[]int{2, 1, 1}, []int{2, 2, 2}},
+	{[]int{0, 0, 2}, []int{4, 3, 3}},
+}
+
+func TestIsSafe(t *testing.T) {
+	tests := []struct {
+		name      string
+		processes []bankerProcess
+		available []int
+		safe      bool
+	}{
+		{"без процессов", nil, []int{0, 0, 0}, true},
+		{"классический пример", textbookProcesses, []int{3, 3, 2}, true},
+		{"после выделения P1 (1,0,2)", []bankerProcess{
+			{[]int{0, 1, 0}, []int{7, 5, 3}},
+			{[]int{3, 0, 2}, []int{3, 2, 2}},
+			{[]int{3, 0, 2}, []int{9, 0, 2}},
+			{[]int{2, 1, 1}, []int{2, 2, 2}},
+			{[]int{0, 0, 2}, []int{4, 3, 3}},
+		}, []int{2, 3, 0}, true},
+		{"после выделения P0 (0,2,0)", []bankerProcess{
+			{[]int{0, 3, 0}, []int{7, 5, 3}},
+			{[]int{3, 0, 2}, []int{3, 2, 2}},
+			{[]int{3, 0, 2}, []int{9, 0, 2}},
+			{[]int{2, 1, 1}, []int{2, 2, 2}},
+			{[]int{0, 0, 2}, []int{4, 3, 3}},
+		}, []int{2, 1, 0}, false},
+		{"свободных ресурсов нет", textbookProcesses, []int{0, 0, 0}, false},
+		{"потребность исчерпана", []bankerProcess{
+			{[]int{7, 5, 3}, []int{7, 5, 3}},
+			{[]int{3, 0, 4}, []int{3, 0, 4}},
+		}, []int{0, 0, 0}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, 1, nil)
+
+			pt := GetProcessTable()
+			for i, p := range test.processes {
+				pt.Add(Process{Name: "p", PID: i + 1, MaxClaim: p.maxClaim, Allocation: p.allocation})
+			}
+			rm := GetResourceManager()
+			rm.Available = test.available
+
+			if safe := rm.IsSafe(); safe != test.safe {
+				t.Errorf("IsSafe() = %v, ожидалось %v", safe, test.safe)
+			}
+		})
+	}
+}
+
+func TestMaxClaimValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxClaim []int
+		valid    bool
+	}{
+		{"случайная потребность", nil, true},
+		{"в пределах ресурсов", []int{10, 0, 7}, true},
+		{"нулевая потребность", []int{0, 0, 0}, true},
+		{"больше ресурсов системы", []int{11, 0, 0}, false},
+		{"отрицательная", []int{0, -1, 0}, false},
+		{"не все типы ресурсов", []int{1, 1}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := ProcessSpec{Name: "p", Memory: 1, Cycles: 1, MaxClaim: test.maxClaim}
+			if err := spec.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() = %v, ожидалась допустимость %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestCreateProcessMaxClaim(t *testing.T) {
+	startModel(t, 1, nil)
+
+	pt := GetProcessTable()
+	claim := []int{4, 0, 2}
+	if err := pt.CreateProcess(ProcessSpec{Name: "p", Memory: 1, Cycles: 1, MaxClaim: claim}); err != nil {
+		t.Fatal(err)
+	}
+
+	process := pt.table[len(pt.table)-1]
+	for r := range claim {
+		if process.MaxClaim[r] != claim[r] {
+			t.Fatalf("MaxClaim = %v, ожидалось %v", process.MaxClaim, claim)
+		}
+	}
+}
+
+func TestDefaultConfigCompletes(t *testing.T) {
+	const processes, maxTicks = 30, 30000
+
+	for seed := int64(1); seed <= 5; seed++ {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			session := startModel(t, seed, nil)
+			if err := session.Do(Action{Kind: ActionCreateRandom, Value: processes}); err != nil {
+				t.Fatal(err)
+			}
+
+			pt := GetProcessTable()
+			for pt.tick < maxTicks && len(pt.table) > 1 {
+				session.Step()
+			}
+			if len(pt.finished) != processes {
+				t.Fatalf("за %d тактов завершено %d процессов из %d", pt.tick, len(pt.finished), processes)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// consoleLength - число последних строк вывода, хранимых консолью
+const consoleLength = 500
+
+// consoleHeight - высота окна консоли
+const consoleHeight = 14
+
+// commandConsole - консоль команд в нижней части экрана: вывод выполненных команд и строка ввода
+// Окно создается заново при каждом открытии под текущий размер экрана, вывод сохраняется между открытиями
+type commandConsole struct {
+	dialog *uitools.Dialog
+	output *uitools.ListBox
+	lines  []string
+}
+
+// Open открывает окно консоли с фокусом на строке ввода
+func (c *commandConsole) Open() {
+	theme := uitools.CurrentTheme
+
+	height := consoleHeight
+	if height > screenHeight-2 {
+		height = screenHeight - 2
+	}
+	x, y, width := 0, screenHeight-1-height, screenWidth
+
+	c.output = uitools.NewListBox(0, 0, 0, 0, c.lines, theme.Normal.Fg, theme.Normal.Bg, nil)
+	c.output.Scroll(len(c.lines))
+	input := uitools.NewTextInput(0, 0, width-2, "", theme.Normal.Fg, theme.Normal.Bg, c.execute)
+
+	focus := uitools.NewFocusGroup(input, c.output)
+	focus.Focus(input)
+	layout := uitools.NewVerticalLayout(0).AddStretch(c.output).Add(input).WithFocus(focus)
+	layout.Arrange(x+1, y+1, width-2, height-2)
+
+	c.dialog = uitools.NewDialog(x, y, width, height, "Консоль (help - список команд, Esc - закрыть)", theme.Normal.Fg, theme.Normal.Bg, layout)
+	c.dialog.Open()
+}
+
+// execute выполняет введенную команду и добавляет ее вывод в консоль
+func (c *commandConsole) execute(t *uitools.TextInput) {
+	line := strings.TrimSpace(t.Text())
+	t.SetText("")
+	if line == "" {
+		return
+	}
+
+	c.print("> " + line)
+	output, err := ExecuteCommand(line)
+	if output != "" {
+		c.print(output)
+	}
+	if err != nil {
+		c.print("ошибка: " + err.Error())
+	}
+
+	c.output.SetItems(c.lines)
+	c.output.Scroll(len(c.lines))
+}
+
+// print добавляет строки текста в вывод консоли, старые строки отбрасываются
+func (c *commandConsole) print(text string) {
+	c.lines = append(c.lines, strings.Split(text, "\n")...)
+	if len(c.lines) > consoleLength {
+		c.lines = c.lines[len(c.lines)-consoleLength:]
+	}
+}
+
+// HandleEvent передает событие открытому окну консоли
+func (c *commandConsole) HandleEvent(ev *termbox.Event) bool {
+	return c.dialog != nil && c.dialog.HandleEvent(ev)
+}
+
+// handleResize пересоздает открытое окно консоли под новый размер экрана
+func (c *commandConsole) handleResize(ev *termbox.Event) bool {
+	if c.dialog != nil && c.dialog.Visible() {
+		c.Open()
+	}
+	return false
+}
+
+// Draw отображает открытое окно консоли
+func (c *commandConsole) Draw() {
+	if c.dialog != nil {
+		c.dialog.Draw()
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// runUntilLimit - наибольшее число тактов, выполняемых одной командой step или run until
+const runUntilLimit = 100000
+
+// command - команда консоли: имя, справка и обработчик аргументов, возвращающий вывод команды
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) (string, error)
+}
+
+// commands - команды консоли в порядке вывода справки
+var commands = []command{
+	{"spawn", "spawn [имя память такты [приоритет [cpu|mixed|io]] [claim=R1,R2,...] [affinity=МАСКА]] - создать процесс с заявленной потребностью в ресурсах и привязкой к процессорам, без параметров - случайный", spawnCommand},
+	{"kill", "kill PID - завершить процесс", signalCommand("kill", SigKill)},
+	{"suspend", "suspend PID - приостановить процесс", signalCommand("suspend", SigStop)},
+	{"resume", "resume PID - возобновить процесс", signalCommand("resume", SigCont)},
+	{"block", "block PID - блокировать процесс до разблокировки", processCommand(ActionBlock)},
+	{"unblock", "unblock PID - разблокировать процесс", processCommand(ActionUnblock)},
+	{"priority", "priority PID N - задать приоритет процесса", valueCommand(ActionPriority)},
+	{"step", "step [N] - выполнить N тактов, по умолчанию один", stepCommand},
+	{"run", "run until tick N | idle | exit PID - выполнять такты до такта N, завершения всех процессов или процесса PID", runCommand},
+	{"set", "set quantum PID N | set параметр значение - задать квант процесса или параметр конфигурации", setCommand},
+	{"show", "show mmu | processes - вывести сегменты памяти или таблицу процессов", showCommand},
+	{"export", "export префикс - выгрузить статистику в CSV и JSON", exportCommand},
+}
+
+// ioProfileNames - имена профилей ввода-вывода в командах
+var ioProfileNames = map[string]IOProfile{"cpu": CPUBound, "mixed": Balanced, "io": IOBound}
+
+// spawnOptions - именованные параметры команды spawn вида имя=значение
+var spawnOptions = map[string]func(spec *ProcessSpec, value string) error{
+	"claim": func(spec *ProcessSpec, value string) (err error) {
+		spec.MaxClaim, err = parseIntList(value, "claim")
+		return err
+	},
+	"affinity": func(spec *ProcessSpec, value string) (err error) {
+		spec.Affinity, err = parseMask(value, "affinity")
+		return err
+	},
+}
+
+// isSpawnOption проверяет, является ли аргумент команды spawn именованным параметром с известным ключом
+func isSpawnOption(arg string) bool {
+	key, _, found := strings.Cut(arg, "=")
+	_, known := spawnOptions[key]
+
+	return found && known
+}
+
+// ExecuteCommand разбирает и выполняет строку команды консоли и возвращает вывод команды
+// Команды, меняющие модель, выполняются действиями сеанса и попадают в запись
+func ExecuteCommand(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	name, args := fields[0], fields[1:]
+	if name == "help" {
+		return helpText(), nil
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args)
+		}
+	}
+
+	return "", fmt.Errorf("неизвестная команда %q, help - список команд", name)
+}
+
+// RunScript выполняет команды из r по одной в строке и пишет их вывод в w
+// Пустые строки и строки, начинающиеся с '#', пропускаются, ошибка команды не прерывает сценарий
+func RunScript(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		output, err := ExecuteCommand(line)
+		if output != "" {
+			fmt.Fprintln(w, output)
+		}
+		if err != nil {
+			fmt.Fprintln(w, "ошибка:", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// helpText возвращает справку по командам
+func helpText() string {
+	lines := []string{"help - список команд"}
+	for _, c := range commands {
+		lines = append(lines, c.usage)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// parseInt разбирает целочисленный аргумент команды с указанием его названия в сообщении об ошибке
+func parseInt(arg, caption string) (int, error) {
+	value, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%s: ожидается целое число, получено %q", caption, arg)
+	}
+
+	return value, nil
+}
+
+// parseIntList разбирает список целых чисел через запятую
+func parseIntList(arg, caption string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(arg, ",") {
+		value, err := parseInt(field, caption)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// parseMask разбирает маску процессоров в десятичной, двоичной (0b), восьмеричной (0o) или шестнадцатеричной (0x) записи
+func parseMask(arg, caption string) (uint64, error) {
+	mask, err := strconv.ParseUint(arg, 0, 64)
+	if err != nil || mask == 0 {
+		return 0, fmt.Errorf("%s: ожидается непустая маска процессоров, например 0b0101, получено %q", caption, arg)
+	}
+
+	return mask, nil
+}
+
+// expectArgs проверяет число аргументов команды
+func expectArgs(args []string, count int, usage string) error {
+	if len(args) != count {
+		return fmt.Errorf("использование: %s", usage)
+	}
+
+	return nil
+}
+
+// spawnCommand создает случайные процессы или процесс с заданными параметрами
+func spawnCommand(args []string) (string, error) {
+	pt := GetProcessTable()
+	pid := pt.processCounter
+
+	if len(args) == 0 {
+		if err := GetSession().Do(Action{Kind: ActionCreateRandom, Value: 1}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Создан процесс proc%d (PID %d)", pid, pid), nil
+	}
+
+	// Параметрами считаются только последние аргументы с известным ключом, поэтому имя может содержать '='
+	var options []string
+	for len(args) > 0 && isSpawnOption(args[len(args)-1]) {
+		options, args = append(options, args[len(args)-1]), args[:len(args)-1]
+	}
+	if len(args) < 3 || len(args) > 5 {
+		return "", errors.New("использование: spawn [имя память такты [приоритет [cpu|mixed|io]] [claim=R1,R2,...] [affinity=МАСКА]]")
+	}
+
+	var err error
+	spec := ProcessSpec{Name: args[0], IOProfile: CPUBound}
+	if spec.Memory, err = parseInt(args[1], "память"); err != nil {
+		return "", err
+	}
+	if spec.Cycles, err = parseInt(args[2], "такты"); err != nil {
+		return "", err
+	}
+	if len(args) > 3 {
+		if spec.Priority, err = parseInt(args[3], "приоритет"); err != nil {
+			return "", err
+		}
+	}
+	if len(args) > 4 {
+		profile, ok := ioProfileNames[args[4]]
+		if !ok {
+			return "", fmt.Errorf("профиль ввода-вывода: ожидается cpu, mixed или io, получено %q", args[4])
+		}
+		spec.IOProfile = profile
+	}
+	for _, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+		if err := spawnOptions[key](&spec, value); err != nil {
+			return "", err
+		}
+	}
+
+	if err := GetSession().Do(Action{Kind: ActionCreate, Spec: &spec}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Создан процесс %s (PID %d)", spec.Name, pid), nil
+}
+
+// processCommand формирует команду, выполняющую действие над процессом с PID из единственного аргумента
+func processCommand(kind ActionKind) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		if err := expectArgs(args, 1, string(kind)+" PID"); err != nil {
+			return "", err
+		}
+		pid, err := parseInt(args[0], "PID")
+		if err != nil {
+			return "", err
+		}
+
+		return "", GetSession().Do(Action{Kind: kind, PID: pid})
+	}
+}
+
+// signalCommand формирует команду name, отправляющую сигнал процессу
+func signalCommand(name string, signal Signal) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		if err := expectArgs(args, 1, name+" PID"); err != nil {
+			return "", err
+		}
+		pid, err := parseInt(args[0], "PID")
+		if err != nil {
+			return "", err
+		}
+
+		if err := GetSession().Do(Action{Kind: ActionSignal, PID: pid, Value: int(signal)}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s отправлен процессу %d", signal.Stringify(), pid), nil
+	}
+}
+
+// valueCommand формирует команду, выполняющую действие над процессом с числовым значением: команда PID N
+func valueCommand(kind ActionKind) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		if err := expectArgs(args, 2, string(kind)+" PID N"); err != nil {
+			return "", err
+		}
+		pid, err := parseInt(args[0], "PID")
+		if err != nil {
+			return "", err
+		}
+		value, err := parseInt(args[1], "значение")
+		if err != nil {
+			return "", err
+		}
+
+		return "", GetSession().Do(Action{Kind: kind, PID: pid, Value: value})
+	}
+}
+
+// stepCommand выполняет заданное число тактов
+func stepCommand(args []string) (string, error) {
+	count := 1
+	if len(args) > 1 {
+		return "", errors.New("использование: step [N]")
+	}
+	if len(args) == 1 {
+		var err error
+		if count, err = parseInt(args[0], "число тактов"); err != nil {
+			return "", err
+		}
+		if count < 1 || count > runUntilLimit {
+			return "", fmt.Errorf("число тактов должно быть от 1 до %d", runUntilLimit)
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		GetSession().Step()
+	}
+	return fmt.Sprintf("Такт %d", GetProcessTable().tick), nil
+}
+
+// runCommand выполняет такты до выполнения условия, но не более runUntilLimit тактов
+func runCommand(args []string) (string, error) {
+	pt := GetProcessTable()
+	usage := "использование: run until tick N | idle | exit PID"
+	if len(args) < 2 || args[0] != "until" {
+		return "", errors.New(usage)
+	}
+
+	var done func() bool
+	switch {
+	case args[1] == "idle" && len(args) == 2:
+		done = func() bool {
+			for _, p := range pt.table {
+				if p.GID != 1 {
+					return false
+				}
+			}
+			return true
+		}
+	case (args[1] == "tick" || args[1] == "exit") && len(args) == 3:
+		value, err := parseInt(args[2], args[1])
+		if err != nil {
+			return "", err
+		}
+		if args[1] == "tick" {
+			done = func() bool { return pt.tick >= value }
+		} else {
+			if value < 0 || value >= pt.processCounter {
+				return "", fmt.Errorf("процесс %d не создавался", value)
+			}
+			done = func() bool { return pt.find(value) == nil }
+		}
+	default:
+		return "", errors.New(usage)
+	}
+
+	// Таблица процессов заменяется при переходе назад, поэтому условие проверяет текущую таблицу
+	for i := 0; !done(); i++ {
+		if i == runUntilLimit {
+			return "", fmt.Errorf("условие не выполнено за %d тактов, такт %d", runUntilLimit, pt.tick)
+		}
+		GetSession().Step()
+		pt = GetProcessTable()
+	}
+	return fmt.Sprintf("Такт %d", pt.tick), nil
+}
+
+// setCommand задает квант процесса или параметр конфигурации, логические параметры принимают on или off
+func setCommand(args []string) (string, error) {
+	if len(args) == 3 && args[0] == "quantum" {
+		return valueCommand(ActionQuantum)(args[1:])
+	}
+	if len(args) != 2 {
+		return "", errors.New("использование: set quantum PID N | set параметр значение")
+	}
+
+	value := 0
+	switch args[1] {
+	case "on", "true":
+		value = 1
+	case "off", "false":
+	default:
+		var err error
+		if value, err = parseInt(args[1], args[0]); err != nil {
+			return "", err
+		}
+	}
+
+	if err := GetSession().Do(Action{Kind: ActionConfig, Key: args[0], Value: value}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", args[0], args[1]), nil
+}
+
+// showCommand выводит сегменты памяти или таблицу процессов
+func showCommand(args []string) (string, error) {
+	if len(args) == 1 && args[0] == "mmu" {
+		return formatMMU(), nil
+	}
+	if len(args) == 1 && args[0] == "processes" {
+		return formatProcesses(), nil
+	}
+
+	return "", errors.New("использование: show mmu | processes")
+}
+
+// exportCommand выгружает статистику в файлы с заданным префиксом
+func exportCommand(args []string) (string, error) {
+	if err := expectArgs(args, 1, "export префикс"); err != nil {
+		return "", err
+	}
+
+	paths, err := ExportStats(args[0])
+	if err != nil {
+		return "", err
+	}
+	return exportSummary(paths), nil
+}
+
+// formatMMU формирует описание занятости памяти и список сегментов оперативной памяти
+func formatMMU() string {
+	mmu := GetMMU()
+	f := mmu.Fragmentation()
+	owners := blockOwners()
+
+	lines := []string{
+		fmt.Sprintf("ОЗУ: занято %d из %d, диск: занято %d из %d", mmu.OccupiedRAM, MaxRAM, mmu.OccupiedDisk, MaxDiskSpace),
+		fmt.Sprintf("Размещение %s, пустых сегментов %d, наибольший %d, внешняя фрагментация %.0f%%, неудачных выделений %d",
+			GetConfig().Placement, f.Holes, f.LargestHole, f.External*100, f.FailedAllocations),
+		fmt.Sprintf("%10s %10s  %s", "Начало", "Размер", "Владелец"),
+	}
+	for e := mmu.blockList.Front(); e != nil; e = e.Next() {
+		block := e.Value.(*MemoryBlockNode)
+		owner := "пусто"
+		if p, ok := owners[block]; ok {
+			owner = fmt.Sprintf("%s (PID %d)", p.Name, p.PID)
+		} else if block.NodeType == MemProcess {
+			owner = "занято"
+		}
+		lines = append(lines, fmt.Sprintf("%10d %10d  %s", block.Position, block.Size, owner))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatProcesses формирует таблицу процессов со столбцами таблицы экрана процессов
+func formatProcesses() string {
+	line := func(cells []string) string {
+		parts := make([]string, len(processTableColumns))
+		for i, c := range processTableColumns {
+			parts[i] = fmt.Sprintf("%*s", c.Width, cells[i])
+		}
+		return strings.Join(parts, " ")
+	}
+
+	titles := make([]string, len(processTableColumns))
+	for i, c := range processTableColumns {
+		titles[i] = c.Title
+	}
+
+	lines := []string{line(titles)}
+	for _, row := range processRows(GetProcessTable().table) {
+		lines = append(lines, line(row))
+	}
+
+	return strings.Join(lines, "\n")
+}
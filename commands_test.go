@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExecuteCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		// output - подстрока вывода команды, пустая строка - вывод не проверяется
+		output string
+		valid  bool
+		// check проверяет последний процесс таблицы после команды, nil - без проверки
+		check func(p *Process) bool
+	}{
+		{"", "", true, nil},
+		{"help", "spawn", true, nil},
+		{"frobnicate", "", false, nil},
+		{"spawn", "Создан процесс proc1 (PID 1)", true, nil},
+		{"spawn a 10 20", "Создан процесс a (PID 1)", true, func(p *Process) bool {
+			return p.Name == "a" && p.Memory == 10 && p.CyclesRemains == 20 && p.Affinity == allCPUsMask()
+		}},
+		{"spawn a 10 20 2 io", "", true, func(p *Process) bool { return p.Priority == 2 && p.IOProfile == IOBound }},
+		{"spawn a 10", "", false, nil},
+		{"spawn a 10 20 1 mixed extra", "", false, nil},
+		{"spawn a x 20", "", false, nil},
+		{"spawn a 10 20 5", "", false, nil},
+		{"spawn a 10 20 1 disk", "", false, nil},
+		{"spawn a 10 20 claim=1,2,3", "", true, func(p *Process) bool { return reflect.DeepEqual(p.MaxClaim, []int{1, 2, 3}) }},
+		{"spawn a 10 20 claim=1,2", "", false, nil},
+		{"spawn a 10 20 claim=1,x,3", "", false, nil},
+		{"spawn a 10 20 affinity=0b10", "", true, func(p *Process) bool { return p.Affinity == 0b10 }},
+		{"spawn a 10 20 1 cpu affinity=0x1 claim=0,0,0", "", true, func(p *Process) bool {
+			return p.Affinity == 1 && p.Priority == 1 && reflect.DeepEqual(p.MaxClaim, []int{0, 0, 0})
+		}},
+		{"spawn a 10 20 affinity=0b100", "", false, nil},
+		{"spawn a 10 20 affinity=0", "", false, nil},
+		{"spawn a 10 20 color=red", "", false, nil},
+		{"spawn a 10 20 affinity=1 3", "", false, nil},
+		{"spawn a=b 10 20", "Создан процесс a=b (PID 1)", true, func(p *Process) bool { return p.Name == "a=b" }},
+		{"spawn a=b 10 20 claim=1,1,1", "", true, func(p *Process) bool {
+			return p.Name == "a=b" && reflect.DeepEqual(p.MaxClaim, []int{1, 1, 1})
+		}},
+		{"kill 99", "", false, nil},
+		{"kill x", "", false, nil},
+		{"block", "", false, nil},
+		{"priority 0 1 2", "", false, nil},
+		{"step", "Такт 1", true, nil},
+		{"step 5", "Такт 5", true, nil},
+		{"step 0", "", false, nil},
+		{"step 100001", "", false, nil},
+		{"run until tick 7", "Такт 7", true, nil},
+		{"run until exit 3", "", false, nil},
+		{"run while idle", "", false, nil},
+		{"set BankerMode on", "BankerMode = on", true, nil},
+		{"set ContextSwitchCost -1", "", false, nil},
+		{"set TickInterval 10", "", false, nil},
+		{"set quantum 99 4", "", false, nil},
+		{"show mmu", "", true, nil},
+		{"show nothing", "", false, nil},
+		{"export", "", false, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			startModel(t, 1, nil)
+
+			output, err := ExecuteCommand(test.line)
+			if (err == nil) != test.valid {
+				t.Fatalf("ошибка %v, ожидалась допустимость %v", err, test.valid)
+			}
+			if !strings.Contains(output, test.output) {
+				t.Fatalf("вывод %q не содержит %q", output, test.output)
+			}
+			if test.check != nil {
+				pt := GetProcessTable()
+				if p := pt.table[len(pt.table)-1]; !test.check(p) {
+					t.Fatalf("параметры созданного процесса не совпадают: %+v", *p)
+				}
+			}
+		})
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	session := startModel(t, 1, nil)
+
+	script := strings.Join([]string{
+		"# комментарий",
+		"",
+		"spawn a 100 50",
+		"unknown",
+		"step 3",
+	}, "\n")
+	var output strings.Builder
+	if err := RunScript(strings.NewReader(script), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Создан процесс a (PID 1)", "ошибка: неизвестная команда \"unknown\", help - список команд", "Такт 3"}
+	if got := strings.Split(strings.TrimSpace(output.String()), "\n"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("вывод сценария %q, ожидался %q", got, want)
+	}
+	if len(session.Actions) != 1 {
+		t.Fatalf("в сеанс записано действий: %d, ожидалось 1", len(session.Actions))
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// schedulerPolicy - вариант планирования для сравнения, меняющий параметры конфигурации
+type schedulerPolicy struct {
+	name  string
+	apply func(*Config)
+}
+
+// schedulerPolicies - варианты планирования: общая очередь, раздельные очереди,
+// раздельные очереди с жесткой привязкой и потоки уровня пользователя
+var schedulerPolicies = []schedulerPolicy{
+	{"shared", func(c *Config) { c.PerCPUQueues, c.HardAffinity = false, false }},
+	{"percpu", func(c *Config) { c.PerCPUQueues, c.HardAffinity = true, false }},
+	{"affinity", func(c *Config) { c.PerCPUQueues, c.HardAffinity = true, true }},
+	{"user-threads", func(c *Config) { c.PerCPUQueues, c.HardAffinity, c.KernelThreads = false, false, false }},
+}
+
+// ComparePolicy - сравниваемая конфигурация: вариант планирования и стратегия размещения в памяти
+type ComparePolicy struct {
+	Scheduler string
+	Placement string
+}
+
+// String возвращает название конфигурации в виде планирование/размещение
+func (p ComparePolicy) String() string {
+	return p.Scheduler + "/" + p.Placement
+}
+
+// ParsePolicies разбирает список конфигураций вида "percpu/best-fit,shared/first-fit"
+// Пустой список означает все сочетания вариантов планирования и стратегий размещения
+func ParsePolicies(text string) ([]ComparePolicy, error) {
+	var policies []ComparePolicy
+	if strings.TrimSpace(text) == "" {
+		for _, s := range schedulerPolicies {
+			for _, p := range placements {
+				policies = append(policies, ComparePolicy{s.name, p})
+			}
+		}
+		return policies, nil
+	}
+
+	for _, item := range strings.Split(text, ",") {
+		parts := strings.Split(strings.TrimSpace(item), "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("конфигурация %q: ожидается планирование/размещение", item)
+		}
+		policy := ComparePolicy{parts[0], parts[1]}
+		if _, err := policy.scheduler(); err != nil {
+			return nil, err
+		}
+		if err := checkPlacement(policy.Placement); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// scheduler возвращает вариант планирования конфигурации
+func (p ComparePolicy) scheduler() (schedulerPolicy, error) {
+	names := make([]string, len(schedulerPolicies))
+	for i, s := range schedulerPolicies {
+		if s.name == p.Scheduler {
+			return s, nil
+		}
+		names[i] = s.name
+	}
+
+	return schedulerPolicy{}, fmt.Errorf("неизвестный вариант планирования %q, доступны: %s", p.Scheduler, strings.Join(names, ", "))
+}
+
+// arrival - процесс нагрузки, поступающий в систему на заданном такте
+type arrival struct {
+	tick int
+	spec ProcessSpec
+}
+
+// generateWorkload формирует нагрузку из count процессов, поступающих с интервалом до 4 тактов
+// Нагрузка формируется отдельным генератором, поэтому одинакова для всех сравниваемых конфигураций
+// Процессы нагрузки не запрашивают ресурсы: сравнение касается планирования и размещения в памяти,
+// а ожидание ресурсов и взаимоблокировки сделали бы прогоны несравнимыми
+func generateWorkload(seed int64, count int) []arrival {
+	r := rand.New(rand.NewSource(seed))
+	workload := make([]arrival, count)
+	tick := 0
+	for i := range workload {
+		tick += r.Intn(5)
+		spec := generateSpec(r, fmt.Sprintf("proc%d", i+1))
+		spec.MaxClaim = make([]int, len(GetConfig().Resources))
+		workload[i] = arrival{tick, spec}
+	}
+
+	return workload
+}
+
+// ComparisonResult - показатели прогона нагрузки в одной конфигурации
+type ComparisonResult struct {
+	Policy ComparePolicy
+	// Ticks - длительность прогона, Completed - число завершенных процессов нагрузки
+	Ticks     int
+	Completed int
+	// Finished сообщает, что все процессы нагрузки завершились до ограничения на число тактов
+	Finished bool
+	// Turnaround и Waiting - среднее время пребывания в системе и ожидания в очереди готовых завершенных процессов
+	Turnaround float64
+	Waiting    float64
+	// Utilization - загрузка процессоров в процентах
+	Utilization float64
+	// Fragmentation - средняя по тактам внешняя фрагментация памяти в процентах
+	Fragmentation float64
+	Swaps         int
+}
+
+// runPolicy прогоняет нагрузку в конфигурации policy, начиная модель с начального значения seed,
+// пока все процессы нагрузки не завершатся или не пройдет maxTicks тактов
+func runPolicy(base Config, policy ComparePolicy, workload []arrival, seed int64, maxTicks int) (ComparisonResult, error) {
+	scheduler, err := policy.scheduler()
+	if err != nil {
+		return ComparisonResult{}, err
+	}
+
+	config := base
+	scheduler.apply(&config)
+	config.Placement = policy.Placement
+	*GetConfig() = config
+	random.Seed(seed)
+	if err := ResetModel(); err != nil {
+		return ComparisonResult{}, err
+	}
+
+	pt := GetProcessTable()
+	result := ComparisonResult{Policy: policy}
+	next, fragmentation := 0, 0.0
+	for pt.tick < maxTicks && (next < len(workload) || len(pt.table) > 1) {
+		for ; next < len(workload) && workload[next].tick <= pt.tick; next++ {
+			if err := pt.CreateProcess(workload[next].spec); err != nil {
+				return result, err
+			}
+		}
+
+		PerformProcess()
+		ScheduleProcess()
+		fragmentation += GetMMU().Fragmentation().External
+	}
+
+	result.Ticks = pt.tick
+	if pt.tick > 0 {
+		result.Fragmentation = fragmentation * 100 / float64(pt.tick)
+	}
+
+	for _, s := range pt.ProcessStats() {
+		result.Swaps += s.Swaps
+		if s.Completion == -1 {
+			continue
+		}
+		result.Completed++
+		result.Turnaround += float64(s.Completion - s.Arrival)
+		result.Waiting += float64(s.WaitTicks)
+	}
+	result.Finished = result.Completed == len(workload)
+	if result.Completed > 0 {
+		result.Turnaround /= float64(result.Completed)
+		result.Waiting /= float64(result.Completed)
+	}
+
+	busy, total := 0, 0
+	for _, cpu := range pt.cpus {
+		busy += cpu.BusyTicks
+		total += cpu.TotalTicks
+	}
+	if total > 0 {
+		result.Utilization = float64(busy) * 100 / float64(total)
+	}
+
+	return result, nil
+}
+
+// RunComparison прогоняет одну и ту же нагрузку из count процессов с одним начальным значением seed
+// в каждой конфигурации и выводит таблицу сравнения в w
+// Средние показатели незавершенного прогона не выводятся: они учитывают только успевшие завершиться процессы
+// Конфигурация модели после сравнения возвращается к исходной
+func RunComparison(w io.Writer, policies []ComparePolicy, seed int64, count, maxTicks int) error {
+	base := *GetConfig()
+	// Нагрузка исходной конфигурации уже создавалась при запуске, поэтому модель восстанавливается без ошибок
+	defer func() {
+		*GetConfig() = base
+		ResetModel()
+	}()
+
+	workload := generateWorkload(seed, count)
+	fmt.Fprintf(w, "Нагрузка: %d процессов, начальное значение %d, не более %d тактов\n", count, seed, maxTicks)
+	fmt.Fprintf(w, "%-24s %7s %10s %10s %9s %9s %9s %9s\n",
+		"Конфигурация", "Тактов", "Завершено", "Оборот", "Ожидание", "Загрузка", "Фрагм.", "Выгрузки")
+
+	unfinished := 0
+	for _, policy := range policies {
+		r, err := runPolicy(base, policy, workload, seed, maxTicks)
+		if err != nil {
+			return fmt.Errorf("%s: %v", policy, err)
+		}
+
+		turnaround, waiting, mark := fmt.Sprintf("%.1f", r.Turnaround), fmt.Sprintf("%.1f", r.Waiting), ""
+		if !r.Finished {
+			turnaround, waiting, mark = "-", "-", " *"
+			unfinished++
+		}
+		fmt.Fprintf(w, "%-24s %7d %10d %10s %9s %8.1f%% %8.1f%% %9d%s\n",
+			policy, r.Ticks, r.Completed, turnaround, waiting, r.Utilization, r.Fragmentation, r.Swaps, mark)
+	}
+	if unfinished > 0 {
+		fmt.Fprintf(w, "* не все процессы нагрузки завершились за %d тактов, средние показатели не сравнимы\n", maxTicks)
+	}
+
+	return nil
+}
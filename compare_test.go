@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunPolicyCompletes(t *testing.T) {
+	const count, maxTicks = 50, 20000
+
+	policies, err := ParsePolicies("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workload := generateWorkload(1, count)
+
+	for _, policy := range policies {
+		t.Run(policy.String(), func(t *testing.T) {
+			startModel(t, 1, nil)
+
+			r, err := runPolicy(*GetConfig(), policy, workload, 1, maxTicks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Finished || r.Completed != count {
+				t.Fatalf("за %d тактов завершено %d процессов из %d", r.Ticks, r.Completed, count)
+			}
+		})
+	}
+}
+
+func TestRunComparisonUnfinished(t *testing.T) {
+	startModel(t, 1, nil)
+
+	tests := []struct {
+		maxTicks int
+		finished bool
+	}{
+		{20000, true},
+		{50, false},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := RunComparison(&out, []ComparePolicy{{"shared", FirstFit}}, 1, 20, test.maxTicks); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		row := strings.Fields(lines[2])
+		marked := row[len(row)-1] == "*"
+		if marked == test.finished || strings.Contains(out.String(), "не сравнимы") == test.finished {
+			t.Fatalf("не более %d тактов: отметка незавершенного прогона %v, ожидалась %v\n%s", test.maxTicks, marked, !test.finished, out.String())
+		}
+		if !test.finished && (row[3] != "-" || row[4] != "-") {
+			t.Fatalf("средние показатели незавершенного прогона выведены: %s", lines[2])
+		}
+	}
+}
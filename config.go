@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Config - параметры симуляции, задаваемые файлом конфигурации
+type Config struct {
+	// BankerMode включает предотвращение тупиков алгоритмом банкира
+	BankerMode bool
+	// Resources - количество экземпляров каждого типа ресурсов
+	Resources []int
+	// CPUCount - число моделируемых процессоров
+	CPUCount int
+	// PerCPUQueues включает раздельные очереди готовых процессов для каждого процессора
+	PerCPUQueues bool
+	// BalanceInterval - период балансировки раздельных очередей в тактах, 0 - без балансировки
+	BalanceInterval int
+	// MigrationPenalty - штраф в тактах на прогрев кэша при переходе процесса на другой процессор
+	MigrationPenalty int
+	// HardAffinity привязывает каждый новый процесс к одному процессору
+	HardAffinity bool
+	// ContextSwitchCost - стоимость переключения контекста в тактах
+	ContextSwitchCost int
+	// KernelThreads включает планирование потоков ядром, иначе планируются процессы (потоки уровня пользователя)
+	KernelThreads bool
+	// MaxThreads - наибольшее число потоков создаваемого процесса
+	MaxThreads int
+	// Theme - встроенная тема интерфейса: default, 256 или mono
+	Theme string
+	// ThemeColors переопределяет цвета темы строками вида "текст/фон", например {"blocking": "red+bold/blue"}
+	ThemeColors map[string]string
+	// TickInterval - длительность такта модели в миллисекундах
+	TickInterval int
+	// Placement - стратегия размещения сегментов в оперативной памяти: first-fit, best-fit или worst-fit
+	Placement string
+	// KeepAllSamples снимает ограничение истории показателей тактов, чтобы выгрузка содержала все такты
+	KeepAllSamples bool
+	// Workload - процессы, создаваемые при запуске модели
+	Workload []ProcessSpec
+	// EventLog - файл, в который дописываются события диспетчера и менеджера памяти, пустая строка - без записи
+	EventLog string
+}
+
+var configOnce sync.Once
+var configInstance *Config
+
+// GetConfig предоставляет единственный экземпляр конфигурации со значениями по умолчанию
+func GetConfig() *Config {
+	configOnce.Do(func() {
+		configInstance = &Config{
+			BankerMode:        true,
+			Resources:         []int{10, 5, 7},
+			CPUCount:          2,
+			PerCPUQueues:      false,
+			BalanceInterval:   16,
+			MigrationPenalty:  2,
+			HardAffinity:      false,
+			ContextSwitchCost: 1,
+			KernelThreads:     true,
+			MaxThreads:        3,
+			Theme:             "default",
+			TickInterval:      100,
+			Placement:         FirstFit,
+		}
+	})
+
+	return configInstance
+}
+
+// LoadConfig читает файл конфигурации в формате JSON поверх значений по умолчанию
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, GetConfig()); err != nil {
+		return err
+	}
+
+	return GetConfig().Validate()
+}
+
+// Validate проверяет допустимость значений конфигурации
+func (c *Config) Validate() error {
+	if err := checkPlacement(c.Placement); err != nil {
+		return err
+	}
+	if c.CPUCount < 1 || c.CPUCount > MaxCPUs {
+		return fmt.Errorf("CPUCount: число процессоров должно быть от 1 до %d", MaxCPUs)
+	}
+	if c.MaxThreads < 1 {
+		return errors.New("MaxThreads: процесс должен иметь хотя бы один поток")
+	}
+	if c.TickInterval < 1 {
+		return errors.New("TickInterval: длительность такта должна быть положительной")
+	}
+	for i, spec := range c.Workload {
+		if err := spec.validate(c); err != nil {
+			return fmt.Errorf("Workload[%d]: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// setConfigValue меняет во время работы параметр конфигурации с именем поля key, логические параметры задаются 0 или 1
+func setConfigValue(key string, value int) error {
+	config := GetConfig()
+
+	flags := map[string]*bool{
+		"BankerMode":     &config.BankerMode,
+		"PerCPUQueues":   &config.PerCPUQueues,
+		"HardAffinity":   &config.HardAffinity,
+		"KernelThreads":  &config.KernelThreads,
+		"KeepAllSamples": &config.KeepAllSamples,
+	}
+	numbers := map[string]*int{
+		"ContextSwitchCost": &config.ContextSwitchCost,
+		"MigrationPenalty":  &config.MigrationPenalty,
+		"BalanceInterval":   &config.BalanceInterval,
+	}
+
+	if flag, ok := flags[key]; ok {
+		*flag = value != 0
+		if key == "PerCPUQueues" {
+			assignQueues()
+		}
+		return nil
+	}
+	if number, ok := numbers[key]; ok {
+		if value < 0 {
+			return fmt.Errorf("%s: значение не может быть отрицательным", key)
+		}
+		*number = value
+		return nil
+	}
+
+	return fmt.Errorf("параметр %q не меняется во время работы", key)
+}
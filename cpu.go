@@ -0,0 +1,170 @@
+package main
+
+// MaxCPUs - наибольшее число процессоров, которое помещается в маску привязки
+const MaxCPUs = 64
+
+// CPU - представление процессора с собственным текущим процессом и указателем Round-Robin
+type CPU struct {
+	ID                     int
+	roundRobinProcessIndex int
+	currentProcess         *Process
+	currentThread          *Thread
+	// BusyTicks - число тактов, в которые процессор исполнял процесс
+	BusyTicks int
+	// TotalTicks - общее число тактов работы процессора
+	TotalTicks int
+	// OverheadTicks - число тактов, потраченных на накладные расходы (переключение контекста, прогрев кэша)
+	OverheadTicks int
+	// Migrations - число процессов, перешедших на этот процессор с другого
+	Migrations int
+	// ContextSwitches - число переключений контекста на процессоре
+	ContextSwitches int
+	// lastProcess - процесс, контекст которого загружен в процессор
+	lastProcess *Process
+	// stallRemains - оставшиеся такты накладных расходов перед исполнением закрепленного процесса
+	stallRemains int
+	// pinned - процесс закреплен за процессором до окончания накладных расходов
+	pinned bool
+}
+
+// IdleTicks возвращает число тактов простоя процессора
+func (cpu *CPU) IdleTicks() int {
+	return cpu.TotalTicks - cpu.BusyTicks - cpu.OverheadTicks
+}
+
+// allCPUsMask возвращает маску привязки, разрешающую исполнение на любом процессоре
+func allCPUsMask() uint64 {
+	return 1<<uint(len(GetProcessTable().cpus)) - 1
+}
+
+// allows проверяет, разрешено ли процессу исполнение на процессоре
+func (cpu *CPU) allows(process *Process) bool {
+	return process.Affinity&(1<<uint(cpu.ID)) != 0
+}
+
+// Utilization возвращает загрузку процессора в процентах
+func (cpu *CPU) Utilization() int {
+	if cpu.TotalTicks == 0 {
+		return 0
+	}
+
+	return cpu.BusyTicks * 100 / cpu.TotalTicks
+}
+
+// isEligible проверяет, может ли процессор рассматривать процесс для исполнения на текущем такте
+func (cpu *CPU) isEligible(process *Process) bool {
+	pt := GetProcessTable()
+
+	// Процесс уже выбран другим процессором
+	// При планировании потоков ядром процесс доступен, пока у него есть свободный поток
+	for _, other := range pt.cpus {
+		if other != cpu && other.currentProcess == process {
+			if !GetConfig().KernelThreads || !process.hasFreeThread() {
+				return false
+			}
+		}
+	}
+
+	// Приостановленный процесс не исполняется до возобновления
+	if process.State == Suspended {
+		return false
+	}
+
+	// Привязка процесса не разрешает исполнение на этом процессоре
+	if process.GID != 1 && !cpu.allows(process) {
+		return false
+	}
+
+	// При раздельных очередях процессор выбирает только процессы своей очереди
+	if GetConfig().PerCPUQueues && process.GID != 1 && process.CPU != cpu.ID {
+		return false
+	}
+
+	return true
+}
+
+// nextCandidate ищет, начиная с указателя Round-Robin, индекс процесса, доступного процессору, или -1
+func (cpu *CPU) nextCandidate() int {
+	pt := GetProcessTable()
+
+	for i := 0; i < len(pt.table); i++ {
+		index := (cpu.roundRobinProcessIndex + i) % len(pt.table)
+		if cpu.isEligible(pt.table[index]) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// QueueLength возвращает число пользовательских процессов в очереди процессора
+func (cpu *CPU) QueueLength() int {
+	length := 0
+	for _, p := range GetProcessTable().table {
+		if p.GID != 1 && p.CPU == cpu.ID {
+			length++
+		}
+	}
+
+	return length
+}
+
+// leastLoadedCPU возвращает процессор с самой короткой очередью среди разрешенных маской привязки
+func leastLoadedCPU(affinity uint64) *CPU {
+	var least *CPU
+	for _, cpu := range GetProcessTable().cpus {
+		if affinity&(1<<uint(cpu.ID)) == 0 {
+			continue
+		}
+		if least == nil || cpu.QueueLength() < least.QueueLength() {
+			least = cpu
+		}
+	}
+
+	return least
+}
+
+// assignQueues заново распределяет процессы по очередям при переключении раздельных очередей во время работы
+// При включенных очередях каждый процесс попадает в самую короткую разрешенную очередь, при выключенных очереди снимаются
+func assignQueues() {
+	pt := GetProcessTable()
+	for _, p := range pt.table {
+		p.CPU = -1
+	}
+	if !GetConfig().PerCPUQueues {
+		return
+	}
+
+	for _, p := range pt.table {
+		if p.GID != 1 {
+			p.CPU = leastLoadedCPU(p.Affinity).ID
+		}
+	}
+}
+
+// BalanceLoad переносит процесс из самой длинной очереди в самую короткую, если их длины заметно различаются
+func BalanceLoad() {
+	pt := GetProcessTable()
+
+	busiest, least := pt.cpus[0], pt.cpus[0]
+	for _, cpu := range pt.cpus[1:] {
+		if cpu.QueueLength() > busiest.QueueLength() {
+			busiest = cpu
+		}
+		if cpu.QueueLength() < least.QueueLength() {
+			least = cpu
+		}
+	}
+
+	if busiest.QueueLength()-least.QueueLength() <= 1 {
+		return
+	}
+
+	// Переносится первый не исполняемый в данный момент процесс очереди, которому разрешен целевой процессор
+	for _, p := range pt.table {
+		if p.GID != 1 && p.CPU == busiest.ID && p.State != Execution && least.allows(p) {
+			p.CPU = least.ID
+			return
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// queueLengths возвращает длины очередей процессоров
+func queueLengths() []int {
+	pt := GetProcessTable()
+	lengths := make([]int, len(pt.cpus))
+	for i, cpu := range pt.cpus {
+		lengths[i] = cpu.QueueLength()
+	}
+
+	return lengths
+}
+
+func TestSwitchToPerCPUQueues(t *testing.T) {
+	session := startModel(t, 1, func(c *Config) { c.CPUCount = 3 })
+	if err := session.Do(Action{Kind: ActionCreateRandom, Value: 10}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		session.Step()
+	}
+
+	pt := GetProcessTable()
+	for _, p := range pt.table {
+		if p.CPU != -1 {
+			t.Fatalf("при общей очереди процесс %d стоит в очереди CPU%d", p.PID, p.CPU)
+		}
+	}
+
+	if err := session.Do(Action{Kind: ActionConfig, Key: "PerCPUQueues", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	lengths := queueLengths()
+	min, max, total := lengths[0], lengths[0], 0
+	for _, l := range lengths {
+		if l < min {
+			min = l
+		}
+		if l > max {
+			max = l
+		}
+		total += l
+	}
+	if total != len(pt.table)-1 || max-min > 1 {
+		t.Fatalf("очереди после включения: %v, процессов %d", lengths, len(pt.table)-1)
+	}
+
+	if err := session.Do(Action{Kind: ActionConfig, Key: "PerCPUQueues", Value: 0}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pt.table {
+		if p.CPU != -1 {
+			t.Fatalf("после выключения очередей процесс %d остался в очереди CPU%d", p.PID, p.CPU)
+		}
+	}
+}
+
+func TestPerCPUQueuesRunOwnQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *Config)
+	}{
+		{"два процессора", func(c *Config) { c.PerCPUQueues = true }},
+		{"четыре процессора", func(c *Config) { c.CPUCount, c.PerCPUQueues = 4, true }},
+		{"без балансировки", func(c *Config) { c.CPUCount, c.PerCPUQueues, c.BalanceInterval = 3, true, 0 }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := startModel(t, 2, test.config)
+			pt := GetProcessTable()
+			for i := 0; i < 500; i++ {
+				if i%10 == 0 {
+					pt.AddProcess()
+				}
+				session.Step()
+
+				for _, cpu := range pt.cpus {
+					if p := cpu.currentProcess; p != nil && p.GID != 1 && p.CPU != cpu.ID {
+						t.Fatalf("такт %d: процесс %d из очереди CPU%d выбран процессором CPU%d", pt.tick, p.PID, p.CPU, cpu.ID)
+					}
+				}
+			}
+
+			for _, cpu := range pt.cpus {
+				if cpu.BusyTicks == 0 {
+					t.Fatalf("CPU%d не исполнил ни одного процесса", cpu.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestBalanceLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		cpus int
+		// queues - очередь каждого процесса, affinity - маска привязки каждого процесса, 0 - все процессоры
+		queues   []int
+		affinity []uint64
+		want     []int
+	}{
+		{"все в одной очереди", 2, []int{0, 0, 0, 0, 0, 0}, nil, []int{3, 3}},
+		{"разница в один процесс", 2, []int{0, 0, 1}, nil, []int{2, 1}},
+		{"три очереди", 3, []int{0, 0, 0, 0, 0, 1}, nil, []int{2, 2, 2}},
+		{"привязка запрещает перенос", 2, []int{0, 0, 0, 0}, []uint64{1, 1, 1, 0}, []int{3, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, 1, func(c *Config) { c.CPUCount, c.PerCPUQueues = test.cpus, true })
+			pt := GetProcessTable()
+			for i, queue := range test.queues {
+				spec := ProcessSpec{Name: "p", Memory: 1, Cycles: 100}
+				if test.affinity != nil {
+					spec.Affinity = test.affinity[i]
+				}
+				if err := pt.CreateProcess(spec); err != nil {
+					t.Fatal(err)
+				}
+				pt.table[len(pt.table)-1].CPU = queue
+			}
+
+			for i := 0; i < len(test.queues); i++ {
+				BalanceLoad()
+			}
+
+			lengths := queueLengths()
+			for i := range lengths {
+				if lengths[i] != test.want[i] {
+					t.Fatalf("очереди после балансировки %v, ожидалось %v", lengths, test.want)
+				}
+			}
+			for _, p := range pt.table[1:] {
+				if !pt.cpus[p.CPU].allows(p) {
+					t.Fatalf("процесс %d перенесен на запрещенный процессор CPU%d", p.PID, p.CPU)
+				}
+			}
+		})
+	}
+}
+
+// runToCompletion выполняет такты сеанса, пока в таблице остаются пользовательские процессы, но не более maxTicks
+// each вызывается после каждого такта, nil - без проверок
+func runToCompletion(t *testing.T, session *Session, maxTicks int, each func()) {
+	t.Helper()
+
+	pt := GetProcessTable()
+	for len(pt.table) > 1 {
+		if pt.tick >= maxTicks {
+			t.Fatalf("за %d тактов не завершились %d процессов", maxTicks, len(pt.table)-1)
+		}
+		session.Step()
+		if each != nil {
+			each()
+		}
+	}
+}
+
+func TestAffinityMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpus     int
+		affinity uint64
+		shared   bool
+	}{
+		{"только CPU1 из двух", 2, 0b10, true},
+		{"только CPU0 из двух", 2, 0b01, true},
+		{"CPU0 и CPU2 из трех", 3, 0b101, true},
+		{"только CPU1 из двух, раздельные очереди", 2, 0b10, false},
+		{"только CPU3 из четырех, раздельные очереди", 4, 0b1000, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := startModel(t, 3, func(c *Config) { c.CPUCount, c.PerCPUQueues = test.cpus, !test.shared })
+			pt := GetProcessTable()
+			var pinned []*Process
+			for i := 0; i < 4; i++ {
+				spec := ProcessSpec{Name: "pinned", Memory: 100, Cycles: 200, Affinity: test.affinity}
+				if err := pt.CreateProcess(spec); err != nil {
+					t.Fatal(err)
+				}
+				pinned = append(pinned, pt.table[len(pt.table)-1])
+				pt.AddProcess()
+			}
+
+			runToCompletion(t, session, 30000, func() {
+				for _, cpu := range pt.cpus {
+					if p := cpu.currentProcess; p != nil && p.GID != 1 && p.Affinity&(1<<uint(cpu.ID)) == 0 {
+						t.Fatalf("такт %d: процесс %d с маской %b выбран процессором CPU%d", pt.tick, p.PID, p.Affinity, cpu.ID)
+					}
+				}
+			})
+			for _, p := range pinned {
+				if p.Completion == -1 {
+					t.Fatalf("процесс %d с привязкой не завершился", p.PID)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationPenalty(t *testing.T) {
+	for _, penalty := range []int{0, 2, 5} {
+		t.Run(fmt.Sprintf("штраф %d", penalty), func(t *testing.T) {
+			session := startModel(t, 4, func(c *Config) { c.ContextSwitchCost, c.MigrationPenalty = 0, penalty })
+			if err := session.Do(Action{Kind: ActionCreateRandom, Value: 8}); err != nil {
+				t.Fatal(err)
+			}
+			runToCompletion(t, session, 30000, nil)
+
+			migrations, overhead := 0, 0
+			for _, cpu := range GetProcessTable().cpus {
+				migrations += cpu.Migrations
+				overhead += cpu.OverheadTicks
+			}
+			if migrations == 0 {
+				t.Fatal("процессы ни разу не перешли на другой процессор")
+			}
+			if overhead != migrations*penalty {
+				t.Fatalf("накладные расходы %d тактов, ожидалось %d переходов * %d", overhead, migrations, penalty)
+			}
+		})
+	}
+}
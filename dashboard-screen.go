@@ -0,0 +1,83 @@
+package main
+
+import (
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// dashboardScreen - экран графиков загрузки процессоров, памяти и очередей процессов за последние такты
+type dashboardScreen struct {
+	charts []*uitools.Chart
+	layout *uitools.Layout
+}
+
+// dashboardSeries - показатели, отображаемые графиками: подпись, шкала (0 - по наибольшему значению) и значение показателя
+var dashboardSeries = []struct {
+	title string
+	max   float64
+	value func(s TickSample) float64
+}{
+	{"Загрузка процессоров, %", 100, func(s TickSample) float64 { return s.CPUUtilization }},
+	{"Занято RAM", MaxRAM, func(s TickSample) float64 { return float64(s.OccupiedRAM) }},
+	{"Занято на диске", 0, func(s TickSample) float64 { return float64(s.OccupiedDisk) }},
+	{"Готовых процессов", 0, func(s TickSample) float64 { return float64(s.Ready) }},
+	{"Заблокированных процессов", 0, func(s TickSample) float64 { return float64(s.Blocked) }},
+}
+
+// newDashboardScreen создает экран графиков, графики делят высоту экрана поровну
+func newDashboardScreen() *dashboardScreen {
+	theme := uitools.CurrentTheme
+	s := &dashboardScreen{layout: uitools.NewVerticalLayout(0)}
+
+	for i, series := range dashboardSeries {
+		style := theme.PaletteStyle(i)
+		chart := uitools.NewChart(0, 0, 0, 0, series.title, series.max, style.Fg, style.Bg)
+		s.charts = append(s.charts, chart)
+		s.layout.AddStretch(chart)
+	}
+
+	s.layout.Arrange(0, 1, screenWidth, screenHeight-2)
+	return s
+}
+
+// Title возвращает название экрана
+func (s *dashboardScreen) Title() string {
+	return "Графики"
+}
+
+// Enter вызывается при переходе на экран
+func (s *dashboardScreen) Enter() {}
+
+// Leave вызывается при уходе с экрана
+func (s *dashboardScreen) Leave() {}
+
+// HandleEvent пересчитывает размеры графиков при изменении размера терминала
+func (s *dashboardScreen) HandleEvent(ev *termbox.Event) bool {
+	if ev.Type == termbox.EventResize {
+		s.layout.Arrange(0, 1, ev.Width, ev.Height-2)
+	}
+
+	return false
+}
+
+// Draw отображает графики показателей за последние такты
+func (s *dashboardScreen) Draw() {
+	// При KeepAllSamples история не ограничена, графики показывают только последние такты
+	samples := GetProcessTable().Samples
+	if n := len(samples) - sampleHistoryLength; n > 0 {
+		samples = samples[n:]
+	}
+	for i, series := range dashboardSeries {
+		values := make([]float64, len(samples))
+		for j, sample := range samples {
+			values[j] = series.value(sample)
+		}
+		s.charts[i].SetValues(values)
+	}
+
+	s.layout.Draw()
+
+	drawStatusBar()
+	drawCPUStatus()
+}
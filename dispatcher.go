@@ -1,76 +1,357 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"operating-systems/processes/uitools"
 	"sync"
-
-	"github.com/nsf/termbox-go"
 )
 
-const (
-	tableUpperBorder = "┌─────┬────────────┬───────────┬─────────────┬─────────┬───────────────┬───────┐"
-	tableHeader      = "│PID  │Имя         │Память     │Состояние    │Время CPU│Осталось тактов│Квант  │"
-	tableSeparator   = "├─────┼────────────┼───────────┼─────────────┼─────────┼───────────────┼───────┤"
-	tableContent     = "│%5d│%12s│%11d│%13s│%9d│%15d│%7d│"
-	tableLowerBorder = "└─────┴────────────┴───────────┴─────────────┴─────────┴───────────────┴───────┘"
-)
+// ioWaitTicks - наибольшая длительность операции ввода-вывода в тактах
+const ioWaitTicks = 4
+
+// processTableColumns - столбцы таблицы процессов
+var processTableColumns = []uitools.Column{
+	{Title: "PID", Width: 5},
+	{Title: "Имя", Width: 12},
+	{Title: "Память", Width: 11},
+	{Title: "Состояние", Width: 13},
+	{Title: "Время CPU", Width: 9},
+	{Title: "Осталось тактов", Width: 15},
+	{Title: "Квант", Width: 7},
+	{Title: "CPU", Width: 3},
+}
 
 // ProcessTable - представление таблицы процессов
 type ProcessTable struct {
-	table                  []Process
-	first                  int
-	processCounter         int
-	roundRobinProcessIndex int
-	currentProcessIndex    int
-	currentProcess         *Process
+	table []*Process
+	// finished - завершенные процессы, их показатели входят в статистику
+	finished       []*Process
+	processCounter int
+	threadCounter  int
+	cpus           []*CPU
+	tick           int
+	// Samples - показатели системы за последние такты, от старых к новым
+	Samples []TickSample
+}
+
+// sampleHistoryLength - число последних тактов, для которых хранятся показатели системы,
+// пока не включен KeepAllSamples
+const sampleHistoryLength = 512
+
+// TickSample - показатели системы на конец такта
+type TickSample struct {
+	Tick int `json:"tick"`
+	// Running - PID процесса, исполнявшегося на каждом процессоре, -1 при простое или накладных расходах
+	Running []int `json:"running"`
+	// CPUUtilization - доля процессоров, исполнявших процессы на такте, в процентах
+	CPUUtilization float64 `json:"cpu_utilization"`
+	OccupiedRAM    int     `json:"occupied_ram"`
+	OccupiedDisk   int     `json:"occupied_disk"`
+	// Ready и Blocked - число пользовательских процессов в состояниях готовности и блокировки
+	Ready   int `json:"ready"`
+	Blocked int `json:"blocked"`
+	// Holes, LargestHole, ExternalFragmentation и FailedAllocations - показатели фрагментации оперативной памяти на такте
+	Holes                 int     `json:"holes"`
+	LargestHole           int     `json:"largest_hole"`
+	ExternalFragmentation float64 `json:"external_fragmentation"`
+	FailedAllocations     int     `json:"failed_allocations"`
 }
 
 // Add добавляет процесс в таблицу и увеличивает счетчик процессов
 func (pt *ProcessTable) Add(process Process) {
-	pt.table = append(pt.table, process)
+	process.StateHistory = []StateChange{{pt.tick, process.State}}
+	process.Arrival, process.Completion = pt.tick, -1
+	for _, t := range process.Threads {
+		t.Parent = &process
+	}
+	pt.table = append(pt.table, &process)
 	pt.processCounter++
 }
 
-// AddProcess добавляет сформированный процесс в таблицу
+// ProcessSpec - параметры создаваемого процесса
+type ProcessSpec struct {
+	Name      string
+	Memory    int
+	Cycles    int
+	Priority  int
+	IOProfile IOProfile
+	// MaxClaim - заявленная максимальная потребность в ресурсах, nil - случайная
+	MaxClaim []int
+	// Affinity - маска разрешенных процессоров, 0 - все процессоры
+	Affinity uint64
+}
+
+// RandomSpec формирует параметры случайного процесса со следующим свободным именем
+func (pt *ProcessTable) RandomSpec() ProcessSpec {
+	return generateSpec(random, fmt.Sprintf("proc%d", pt.processCounter))
+}
+
+// generateSpec формирует параметры случайного процесса с заданным именем генератором r
+func generateSpec(r *rand.Rand, name string) ProcessSpec {
+	return ProcessSpec{Name: name,
+		Memory:    r.Intn(MaxRAM / 256),
+		Cycles:    GetConfig().MaxThreads + r.Intn(1024),
+		Priority:  0,
+		IOProfile: CPUBound}
+}
+
+// Validate проверяет, что процесс с такими параметрами может быть исполнен
+func (spec ProcessSpec) Validate() error {
+	return spec.validate(GetConfig())
+}
+
+// validate проверяет параметры процесса относительно ресурсов и числа процессоров конфигурации c
+func (spec ProcessSpec) validate(c *Config) error {
+	if spec.Name == "" {
+		return errors.New("имя процесса не задано")
+	}
+	if spec.Memory < 0 || spec.Memory > MaxRAM {
+		return fmt.Errorf("объем памяти должен быть от 0 до %d", MaxRAM)
+	}
+	if spec.Cycles < 1 {
+		return errors.New("число тактов должно быть положительным")
+	}
+	if spec.Priority < 0 || spec.Priority > MaxPriority {
+		return fmt.Errorf("приоритет должен быть от 0 до %d", MaxPriority)
+	}
+	if spec.MaxClaim != nil {
+		total := c.Resources
+		if len(spec.MaxClaim) != len(total) {
+			return fmt.Errorf("максимальная потребность должна задаваться для %d типов ресурсов", len(total))
+		}
+		for r, claim := range spec.MaxClaim {
+			if claim < 0 || claim > total[r] {
+				return fmt.Errorf("потребность в ресурсе %d должна быть от 0 до %d", r, total[r])
+			}
+		}
+	}
+	if cpus := uint(c.CPUCount); cpus < MaxCPUs && spec.Affinity>>cpus != 0 {
+		return fmt.Errorf("маска привязки допускает только процессоры от 0 до %d", cpus-1)
+	}
+
+	return nil
+}
+
+// AddProcess добавляет в таблицу случайный процесс
 func (pt *ProcessTable) AddProcess() {
-	proc := Process{Name: fmt.Sprintf("proc%d", pt.processCounter),
-		Memory:        rand.Intn(MaxRAM / 256),
+	pt.CreateProcess(pt.RandomSpec())
+}
+
+// CreateProcess проверяет параметры и добавляет сформированный по ним процесс в таблицу
+func (pt *ProcessTable) CreateProcess(spec ProcessSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	total := GetResourceManager().Total
+	maxClaim := make([]int, len(total))
+	if spec.MaxClaim != nil {
+		copy(maxClaim, spec.MaxClaim)
+	} else {
+		for r := range total {
+			maxClaim[r] = random.Intn(total[r] + 1)
+		}
+	}
+
+	// При жесткой привязке процесс разрешен только на наименее загруженном из допустимых процессоров
+	affinity := allCPUsMask()
+	if spec.Affinity != 0 {
+		affinity = spec.Affinity
+	}
+	if GetConfig().HardAffinity {
+		affinity = 1 << uint(leastLoadedCPU(affinity).ID)
+	}
+
+	// При раздельных очередях процесс попадает в самую короткую очередь
+	cpu := -1
+	if GetConfig().PerCPUQueues {
+		cpu = leastLoadedCPU(affinity).ID
+	}
+
+	// Такты процесса распределяются между его потоками, каждому достается хотя бы один
+	threadCount := 1 + random.Intn(GetConfig().MaxThreads)
+	if threadCount > spec.Cycles {
+		threadCount = spec.Cycles
+	}
+	timeSlot := 1 << uint(spec.Priority)
+
+	proc := Process{Name: spec.Name,
+		Memory:        spec.Memory,
 		MemoryBlock:   nil,
-		CyclesRemains: rand.Intn(1024),
-		TimeSlot:      1,
+		CyclesRemains: spec.Cycles,
+		TimeSlot:      timeSlot,
 		State:         Readiness,
 		PID:           pt.processCounter,
 		CPUTime:       0,
-		GID:           0}
+		GID:           0,
+		PPID:          0,
+		Priority:      spec.Priority,
+		IOProfile:     spec.IOProfile,
+		CPU:           cpu,
+		Affinity:      affinity,
+		LastCPU:       -1,
+		MaxClaim:      maxClaim,
+		Allocation:    make([]int, len(total))}
+
+	for i := 0; i < threadCount; i++ {
+		share := spec.Cycles / threadCount
+		if i == 0 {
+			share += spec.Cycles % threadCount
+		}
+		proc.Threads = append(proc.Threads, &Thread{TID: pt.threadCounter, State: Readiness, CyclesRemains: share, TimeSlot: timeSlot})
+		pt.threadCounter++
+	}
 
 	pt.Add(proc)
+	return nil
 }
 
-// Draw Отображает таблицу на экране в заданной области
-func (pt *ProcessTable) Draw(x, y int) {
-	uitools.Print(x, y, termbox.ColorWhite, termbox.ColorBlue, tableUpperBorder)
-	uitools.Print(x, y+1, termbox.ColorWhite, termbox.ColorBlue, tableHeader)
-	uitools.Print(x, y+2, termbox.ColorWhite, termbox.ColorBlue, tableSeparator)
+// remove удаляет процесс из таблицы и корректирует указатели Round-Robin процессоров
+func (pt *ProcessTable) remove(process *Process) {
+	for i, p := range pt.table {
+		if p == process {
+			pt.table = append(pt.table[:i], pt.table[i+1:]...)
+			for _, cpu := range pt.cpus {
+				if cpu.roundRobinProcessIndex > i {
+					cpu.roundRobinProcessIndex--
+				}
+				if cpu.roundRobinProcessIndex >= len(pt.table) {
+					cpu.roundRobinProcessIndex = 0
+				}
+			}
+			pt.releaseCPUs(process)
+			return
+		}
+	}
+}
 
-	// for i, v := range pt.table {
-	for i := 0; i <= 10; i++ {
-		if len(pt.table) <= i+pt.first {
-			break
+// sample добавляет показатели завершившегося такта в историю, старые записи отбрасываются,
+// если в конфигурации не включено хранение показателей всех тактов
+// running - PID процессов, исполнявшихся на такте на каждом процессоре
+func (pt *ProcessTable) sample(running []int) {
+	mmu := GetMMU()
+	busy := 0
+	for _, pid := range running {
+		if pid != -1 {
+			busy++
 		}
-		v := pt.table[i+pt.first]
-		uitools.Printf(x, y+3+i*2, termbox.ColorWhite, termbox.ColorBlue, tableContent, v.PID, v.Name, v.Memory, v.State.Stringify(), v.CPUTime, v.CyclesRemains, v.TimeSlot)
-		uitools.Print(x, y+4+i*2, termbox.ColorWhite, termbox.ColorBlue, tableSeparator)
 	}
 
-	lbPos := y + 2 + (len(pt.table)-pt.first)*2
+	fragmentation := mmu.Fragmentation()
+	s := TickSample{Tick: pt.tick,
+		Running:               running,
+		CPUUtilization:        float64(busy) * 100 / float64(len(pt.cpus)),
+		OccupiedRAM:           mmu.OccupiedRAM,
+		OccupiedDisk:          mmu.OccupiedDisk,
+		Holes:                 fragmentation.Holes,
+		LargestHole:           fragmentation.LargestHole,
+		ExternalFragmentation: fragmentation.External,
+		FailedAllocations:     fragmentation.FailedAllocations}
+
+	for _, p := range pt.table {
+		if p.GID == 1 {
+			continue
+		}
+		switch p.State {
+		case Readiness:
+			s.Ready++
+		case Blocking:
+			s.Blocked++
+		}
+	}
+
+	pt.Samples = append(pt.Samples, s)
+	if n := len(pt.Samples) - sampleHistoryLength; n > 0 && !GetConfig().KeepAllSamples {
+		pt.Samples = pt.Samples[n:]
+	}
+}
+
+// releaseCPUs освобождает процессоры, выбравшие процесс или закрепленные за ним
+func (pt *ProcessTable) releaseCPUs(process *Process) {
+	for _, cpu := range pt.cpus {
+		if cpu.currentProcess == process {
+			cpu.currentProcess, cpu.currentThread = nil, nil
+			cpu.pinned, cpu.stallRemains = false, 0
+		}
+	}
+}
+
+// terminate удаляет процесс из таблицы, возвращая его ресурсы, память и место на диске,
+// и переносит его в список завершенных
+// Повторный вызов для уже удаленного процесса ничего не меняет
+func (pt *ProcessTable) terminate(process *Process) {
+	mmu := GetMMU()
+	if !pt.contains(process) {
+		return
+	}
+
+	GetResourceManager().Release(process)
+	freeMemory(process, -1)
+	if process.Swapped {
+		mmu.ReleaseDisk(process.Memory)
+		process.Swapped = false
+	}
+	process.SetState(Terminated)
+	process.Completion = pt.tick
+	pt.remove(process)
+	pt.finished = append(pt.finished, process)
+}
 
-	if len(pt.table)-pt.first > 10 {
-		lbPos = y + 2 + 11*2
+// freeMemory освобождает сегмент процесса без выгрузки на диск, если процесс занимает оперативную память
+func freeMemory(process *Process, cpu int) {
+	block := process.MemoryBlock
+	if block == nil {
+		return
 	}
 
-	uitools.Print(x, lbPos, termbox.ColorWhite, termbox.ColorBlue, tableLowerBorder)
+	GetEventLog().Log(EventFreed, process.PID, cpu, "начало %d, размер %d", block.Position, block.Size)
+	GetMMU().Free(block, false)
+	process.MemoryBlock = nil
+}
+
+// swapOut выгружает сегмент процесса на диск и сообщает, удалась ли выгрузка
+func swapOut(process *Process, cpu int) bool {
+	block := process.MemoryBlock
+	position, size := block.Position, block.Size
+
+	if !GetMMU().Free(block, true) {
+		GetEventLog().Log(EventSwapFailed, process.PID, cpu, "размер %d, диск занят на %d из %d", size, GetMMU().OccupiedDisk, MaxDiskSpace)
+		return false
+	}
+
+	GetEventLog().Log(EventSwappedOut, process.PID, cpu, "начало %d, размер %d", position, size)
+	process.MemoryBlock = nil
+	process.Swapped = true
+	process.Swaps++
+	return true
+}
+
+// contains проверяет, находится ли процесс в таблице
+func (pt *ProcessTable) contains(process *Process) bool {
+	for _, p := range pt.table {
+		if p == process {
+			return true
+		}
+	}
+
+	return false
+}
+
+// processRows формирует строки таблицы процессов для отображения в заданном порядке
+func processRows(processes []*Process) [][]string {
+	rows := make([][]string, len(processes))
+	for i, v := range processes {
+		cpu := "-"
+		if v.CPU != -1 {
+			cpu = fmt.Sprint(v.CPU)
+		}
+		rows[i] = []string{fmt.Sprint(v.PID), v.Name, fmt.Sprint(v.Memory), v.State.Stringify(),
+			fmt.Sprint(v.CPUTime), fmt.Sprint(v.CyclesRemains), fmt.Sprint(v.TimeSlot), cpu}
+	}
+
+	return rows
 }
 
 var once sync.Once
@@ -79,65 +360,220 @@ var tableInstance *ProcessTable
 // GetProcessTable предоставляет глобальный и единственный экземпляр таблицы процессов
 func GetProcessTable() *ProcessTable {
 	once.Do(func() {
-		tableInstance = &ProcessTable{first: 0, processCounter: 0, roundRobinProcessIndex: -1}
+		tableInstance = &ProcessTable{processCounter: 0}
 	})
 	return tableInstance
 }
 
-// InitDispatcher инициализирует таблицу процессом init
-func InitDispatcher() {
-	GetProcessTable().Add(Process{Name: "init",
+// ResetModel возвращает модель в исходное состояние: таблица процессов, память и ресурсы создаются заново,
+// журнал событий очищается, а таблица инициализируется процессом init и процессами нагрузки
+func ResetModel() error {
+	once = sync.Once{}
+	memOnce = sync.Once{}
+	bankerOnce = sync.Once{}
+	GetEventLog().Events = nil
+
+	return InitDispatcher()
+}
+
+// InitDispatcher инициализирует таблицу процессом init, создает процессоры и процессы нагрузки из конфигурации
+// Возвращает ошибку, если процесс нагрузки не может быть создан
+func InitDispatcher() error {
+	pt := GetProcessTable()
+	for i := 0; i < GetConfig().CPUCount; i++ {
+		pt.cpus = append(pt.cpus, &CPU{ID: i})
+	}
+
+	pt.Add(Process{Name: "init",
 		Memory:        0,
 		MemoryBlock:   nil,
 		PID:           0,
 		GID:           1,
+		PPID:          -1,
 		CyclesRemains: 0,
-		State:         Readiness})
+		State:         Readiness,
+		CPU:           -1,
+		LastCPU:       -1})
+
+	for i, spec := range GetConfig().Workload {
+		if err := pt.CreateProcess(spec); err != nil {
+			return fmt.Errorf("Workload[%d]: %v", i, err)
+		}
+	}
+
+	return nil
 }
 
-// ScheduleProcess выбирает процесс для выполнения по схеме Round-Robin с растущими квантами времени
+// ScheduleProcess выбирает процессы для выполнения на каждом процессоре по схеме Round-Robin с растущими квантами времени
 func ScheduleProcess() {
+	pt := GetProcessTable()
+
+	// Процессы прошлого такта освобождают процессоры до выбора новых, кроме закрепленных
+	for _, cpu := range pt.cpus {
+		if !cpu.pinned {
+			cpu.currentProcess, cpu.currentThread = nil, nil
+		}
+	}
+
+	for _, cpu := range pt.cpus {
+		cpu.Schedule()
+	}
+}
+
+// Schedule выбирает процесс для выполнения на процессоре
+func (cpu *CPU) Schedule() {
 	// Последовательность
-	// Выбор нового процесса для исполнения
-	// Если до этого в таблице не было процессов, выбирается первый
-	// Если процесс не блокирован, попытка доступа к памяти
+	// Выбор нового процесса для исполнения, не занятого другим процессором
+	// Если процесс не блокирован, запрос ресурсов и попытка доступа к памяти
 	// Если ресурс доступен, текущий процесс помечается выбранным для исполнения
 
 	pt := GetProcessTable()
-	if pt.processCounter > 0 && pt.roundRobinProcessIndex == -1 {
-		pt.roundRobinProcessIndex = 0
+
+	// Закрепленный процесс остается на процессоре, пока идут накладные расходы
+	if cpu.pinned {
+		if cpu.currentProcess.State == Blocking {
+			cpu.stallRemains = 0
+		}
+		if cpu.stallRemains == 0 {
+			cpu.pinned = false
+		}
+		return
+	}
+
+	index := cpu.nextCandidate()
+	if index == -1 {
+		return
 	}
 
-	pt.currentProcess = &pt.table[pt.roundRobinProcessIndex]
+	cpu.roundRobinProcessIndex = index
+	cpu.currentProcess = pt.table[index]
 
 	// Идентификатор группы == 1 => особый процесс init, исполняемым не помечается
-	if pt.currentProcess.GID == 1 {
+	if cpu.currentProcess.GID == 1 {
 		return
 	}
 
 	// Если блокируется - надо дождаться выхода из блокировки, не помечается исполняемым
-	if pt.currentProcess.State == Blocking {
+	if cpu.currentProcess.State == Blocking {
+		return
+	}
+
+	// Процесс запрашивает ресурсы, небезопасный или невыполнимый запрос откладывается
+	cpu.currentProcess.GenerateRequest()
+	if !GetResourceManager().Request(cpu.currentProcess) {
+		return
+	}
+
+	// Выбор потока процесса, все потоки могут быть заняты другими процессорами
+	cpu.currentThread = cpu.currentProcess.nextThread(cpu)
+	if cpu.currentThread == nil {
 		return
 	}
 
 	// Если ресурс памяти уже в RAM, выбирается для исполнения
-	if pt.currentProcess.MemoryBlock != nil {
-		pt.currentProcessIndex = pt.roundRobinProcessIndex
-		pt.currentProcess.State = Execution
+	if cpu.currentProcess.MemoryBlock != nil {
+		cpu.dispatch()
 		return
 	}
 
 	// Попытка резервирования памяти
-	block := GetMMU().Add(pt.currentProcess.Memory)
+	// Выгруженный ранее сегмент при загрузке освобождает место на диске
+	block := GetMMU().Add(cpu.currentProcess.Memory)
 	if block != nil {
-		pt.currentProcess.MemoryBlock = block
-		pt.currentProcessIndex = pt.roundRobinProcessIndex
-		pt.currentProcess.State = Execution
+		if cpu.currentProcess.Swapped {
+			GetMMU().ReleaseDisk(cpu.currentProcess.Memory)
+			cpu.currentProcess.Swapped = false
+		}
+		cpu.currentProcess.MemoryBlock = block
+		GetEventLog().Log(EventAllocated, cpu.currentProcess.PID, cpu.ID, "начало %d, размер %d", block.Position, block.Size)
+		cpu.dispatch()
 	}
 }
 
-// PerformProcess выполняет процесс, меняет его состояние, либо выгружает из оперативной памяти, либо завершает процесс
+// dispatch помечает текущий процесс исполняемым на процессоре
+// Если загружается контекст другого процесса или процесс перешел с другого процессора,
+// он закрепляется на время накладных расходов
+func (cpu *CPU) dispatch() {
+	p := cpu.currentProcess
+	p.SetState(Execution)
+	cpu.currentThread.State = Execution
+
+	if cpu.lastProcess != p {
+		p.ContextSwitches++
+		cpu.ContextSwitches++
+		cpu.stallRemains = GetConfig().ContextSwitchCost
+		cpu.lastProcess = p
+	}
+
+	if p.LastCPU != -1 && p.LastCPU != cpu.ID {
+		p.Migrations++
+		cpu.Migrations++
+		cpu.stallRemains += GetConfig().MigrationPenalty
+	}
+	p.LastCPU = cpu.ID
+
+	if cpu.stallRemains > 0 {
+		cpu.pinned = true
+	}
+
+	GetEventLog().Log(EventScheduled, p.PID, cpu.ID, "поток %d, накладные расходы %d", cpu.currentThread.TID, cpu.stallRemains)
+}
+
+// PerformProcess выполняет процессы на всех процессорах и удаляет завершенные из таблицы
 func PerformProcess() {
+	pt := GetProcessTable()
+
+	// Сигналы доставляются до исполнения, чтобы процессоры не исполняли завершенные и приостановленные процессы
+	pt.deliverSignals()
+
+	running := make([]int, len(pt.cpus))
+	var removing []*Process
+	for i, cpu := range pt.cpus {
+		busyTicks := cpu.BusyTicks
+		running[i] = -1
+		if cpu.currentProcess != nil {
+			running[i] = cpu.currentProcess.PID
+		}
+		if cpu.Perform() {
+			removing = append(removing, cpu.currentProcess)
+		}
+		if cpu.BusyTicks == busyTicks {
+			running[i] = -1
+		}
+	}
+
+	// Удаление процессов из таблицы
+	for _, p := range removing {
+		pt.terminate(p)
+	}
+
+	// Готовые процессы копят время ожидания, завершение операций ввода-вывода снимает блокировку
+	for _, p := range pt.table {
+		if p.State == Readiness && p.GID != 1 {
+			p.WaitTicks++
+		}
+		if p.State == Blocking && p.IOWaitRemains > 0 {
+			p.IOWaitRemains--
+			if p.IOWaitRemains == 0 {
+				p.SetState(Readiness)
+				GetEventLog().Log(EventUnblocked, p.PID, -1, "ввод-вывод завершен")
+			}
+		}
+	}
+
+	pt.tick++
+	GetMMU().Sample()
+	pt.sample(running)
+
+	// Периодическая балансировка раздельных очередей
+	if GetConfig().PerCPUQueues && GetConfig().BalanceInterval > 0 && pt.tick%GetConfig().BalanceInterval == 0 {
+		BalanceLoad()
+	}
+}
+
+// Perform выполняет процесс, меняет его состояние, либо выгружает из оперативной памяти, либо завершает процесс
+// Возвращает флаг необходимости удаления процесса из таблицы
+func (cpu *CPU) Perform() bool {
 	// Алгоритм
 	// Если процесс доступен для исполнения, исполняется
 
@@ -148,69 +584,106 @@ func PerformProcess() {
 
 	// Переход к другому процессу
 
-	pt := GetProcessTable()
-
 	// Если текущего процесса Round-Robin нет, пропускаем такт
-	if pt.currentProcess == nil {
-		return
+	if cpu.currentProcess == nil {
+		cpu.TotalTicks++
+		return false
 	}
 
-	// Если процесс не выбран для исполнения, пропускаем такт
-	if pt.currentProcess.State != Execution {
-		// Циклическое смещение указателя Round-Robin
-		pt.roundRobinProcessIndex = (pt.roundRobinProcessIndex + 1) % len(pt.table)
-		return
+	cpu.TotalTicks++
+
+	// Процесс закреплен, но процессор еще занят накладными расходами
+	if cpu.stallRemains > 0 {
+		cpu.stallRemains--
+		cpu.OverheadTicks++
+		return false
+	}
+
+	// Циклическое смещение указателя Round-Robin в пределах таблицы процессов
+	cpu.roundRobinProcessIndex = (cpu.roundRobinProcessIndex + 1) % len(GetProcessTable().table)
+
+	// Если поток не выбран для исполнения или процесс заблокирован, пропускаем такт
+	thread := cpu.currentThread
+	if thread == nil || thread.State != Execution {
+		return false
+	}
+	if cpu.currentProcess.State == Blocking {
+		thread.State = Readiness
+		return false
 	}
 
-	// Пересчет блоков процесса
+	cpu.BusyTicks++
+
+	// При планировании ядром квант принадлежит потоку, иначе - процессу
+	timeSlot := &cpu.currentProcess.TimeSlot
+	if GetConfig().KernelThreads {
+		timeSlot = &thread.TimeSlot
+	}
+	cpu.currentProcess.recordQuantum(*timeSlot)
+
+	// Пересчет блоков потока и процесса
 	// Уменьшаем количество тактов
-	if pt.currentProcess.CyclesRemains-pt.currentProcess.TimeSlot <= 0 {
-		// Если кванта времени хватило на завершение процесса, ставим количество тактов процесса 0
-		// Добавляем к процессорному времмени разность кванта и оставшихся тактов
-		pt.currentProcess.CPUTime += pt.currentProcess.TimeSlot - pt.currentProcess.CyclesRemains
-		pt.currentProcess.CyclesRemains = 0
+	if thread.CyclesRemains-*timeSlot <= 0 {
+		// Если кванта времени хватило на завершение потока, ставим количество тактов потока 0
+		// Добавляем к процессорному времени оставшиеся такты
+		thread.CPUTime += thread.CyclesRemains
+		cpu.currentProcess.CPUTime += thread.CyclesRemains
+		cpu.currentProcess.CyclesRemains -= thread.CyclesRemains
+		thread.CyclesRemains = 0
+		thread.State = Terminated
 	} else {
 		// Иначе, уменьшаем число тактов на квант
 		// Увеличиваем время CPU на квант
 		// Увеличиваем квант в 2 раза
-		pt.currentProcess.CyclesRemains -= pt.currentProcess.TimeSlot
-		pt.currentProcess.CPUTime += pt.currentProcess.TimeSlot
-		pt.currentProcess.TimeSlot <<= 1
+		thread.CyclesRemains -= *timeSlot
+		thread.CPUTime += *timeSlot
+		cpu.currentProcess.CyclesRemains -= *timeSlot
+		cpu.currentProcess.CPUTime += *timeSlot
+		*timeSlot <<= 1
+		thread.State = Readiness
+		GetEventLog().Log(EventPreempted, cpu.currentProcess.PID, cpu.ID, "поток %d, осталось тактов %d, следующий квант %d",
+			thread.TID, thread.CyclesRemains, *timeSlot)
 	}
 
 	mmu := GetMMU()
 	isProcessRemoving := false
 
-	// Процесс завершился
-	if pt.currentProcess.CyclesRemains == 0 {
-		mmu.Free(pt.currentProcess.MemoryBlock, false)
+	// Процесс завершился, когда завершились все его потоки
+	if cpu.currentProcess.CyclesRemains == 0 {
+		// Сегмент мог быть освобожден потоком, завершившимся на другом процессоре
+		freeMemory(cpu.currentProcess, cpu.ID)
 		// Завершение процесса
 		// Удаление процесса из таблицы
+		GetEventLog().Log(EventCompleted, cpu.currentProcess.PID, cpu.ID, "время CPU %d", cpu.currentProcess.CPUTime)
 		isProcessRemoving = true
 	}
 
 	// Если RAM заполнен больше, чем на половину, попытка выгрузить на диск
-	if mmu.OccupiedRAM*2 > MaxRAM {
-		correct := mmu.Free(pt.currentProcess.MemoryBlock, true)
-		if correct {
-			// Если выгрузка произошла успешно, процесс больше не связан с блоками RAM
-			pt.currentProcess.MemoryBlock = nil
-		} else {
+	// Память завершившегося процесса уже освобождена и повторно не выгружается,
+	// сегмент мог быть выгружен потоком того же процесса на другом процессоре
+	if !isProcessRemoving && cpu.currentProcess.MemoryBlock != nil && mmu.OccupiedRAM*2 > MaxRAM {
+		// Если выгрузка произошла успешно, процесс больше не связан с блоками RAM
+		if !swapOut(cpu.currentProcess, cpu.ID) {
 			// Иначе - аварийное завершение процесса
 			// Удаление процесса из таблицы
 			isProcessRemoving = true
 		}
-	} else {
-		// Если RAM мало заполнен, перевод текущего процесса в состояние готовности
-		pt.currentProcess.State = Readiness
 	}
 
-	// Удаление процесса из таблицы
-	if isProcessRemoving {
-		pt.table = append(pt.table[:pt.currentProcessIndex], pt.table[pt.currentProcessIndex+1:]...)
-		pt.roundRobinProcessIndex--
+	// Перевод текущего процесса в состояние готовности
+	// Процесс мог быть завершен потоком на другом процессоре в этом же такте
+	// После кванта процесс может начать операцию ввода-вывода согласно своему профилю
+	switch {
+	case isProcessRemoving:
+		cpu.currentProcess.SetState(Terminated)
+	case cpu.currentProcess.State == Terminated:
+	case random.Float64() < cpu.currentProcess.IOProfile.blockProbability():
+		cpu.currentProcess.IOWaitRemains = 1 + random.Intn(ioWaitTicks)
+		GetEventLog().Log(EventBlocked, cpu.currentProcess.PID, cpu.ID, "ввод-вывод, тактов %d", cpu.currentProcess.IOWaitRemains)
+		cpu.currentProcess.SetState(Blocking)
+	default:
+		cpu.currentProcess.SetState(Readiness)
 	}
 
-	// Циклиеское смещение указателя Round-Robin
-	pt.roundRobinProcessIndex = (pt.roundRobinProcessIndex + 1) % len(pt.table)
+	return isProcessRemoving
 }
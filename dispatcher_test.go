@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// startModel запускает модель заново для теста: configure меняет конфигурацию, затем начинается запись сеанса
+// с начальным значением seed без сохранения в файл
+// Исходные конфигурация и сеанс возвращаются по окончании теста
+func startModel(t *testing.T, seed int64, configure func(c *Config)) *Session {
+	t.Helper()
+
+	GetSession()
+	config := *GetConfig()
+	t.Cleanup(func() {
+		*GetConfig() = config
+		sessionInstance = &Session{}
+		ResetModel()
+	})
+
+	if configure != nil {
+		configure(GetConfig())
+	}
+	session := &Session{}
+	sessionInstance = session
+	session.Record(seed, "")
+	if err := ResetModel(); err != nil {
+		t.Fatal(err)
+	}
+
+	return session
+}
+
+func TestInitDispatcherWorkload(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload []ProcessSpec
+		created  int
+		valid    bool
+	}{
+		{"без нагрузки", nil, 0, true},
+		{"два процесса", []ProcessSpec{{Name: "a", Memory: 10, Cycles: 5}, {Name: "b", Memory: 20, Cycles: 7, MaxClaim: []int{1, 0, 1}}}, 2, true},
+		{"процесс без имени", []ProcessSpec{{Name: "a", Memory: 10, Cycles: 5}, {Memory: 10, Cycles: 5}}, 0, false},
+		{"потребность больше ресурсов", []ProcessSpec{{Name: "a", Memory: 10, Cycles: 5, MaxClaim: []int{100, 0, 0}}}, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, 1, nil)
+			GetConfig().Workload = test.workload
+
+			err := ResetModel()
+			if (err == nil) != test.valid {
+				t.Fatalf("ResetModel() = %v, ожидалась допустимость %v", err, test.valid)
+			}
+			if created := len(GetProcessTable().table) - 1; test.valid && created != test.created {
+				t.Fatalf("создано процессов нагрузки: %d, ожидалось %d", created, test.created)
+			}
+		})
+	}
+}
+
+func TestContextSwitchCost(t *testing.T) {
+	tests := []struct {
+		cost int
+		cpus int
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 1},
+		{3, 2},
+		{5, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("стоимость %d, процессоров %d", test.cost, test.cpus), func(t *testing.T) {
+			session := startModel(t, 5, func(c *Config) {
+				c.CPUCount, c.ContextSwitchCost, c.MigrationPenalty = test.cpus, test.cost, 0
+			})
+			if err := session.Do(Action{Kind: ActionCreateRandom, Value: 6}); err != nil {
+				t.Fatal(err)
+			}
+
+			pt := GetProcessTable()
+			type stall struct {
+				process *Process
+				remains int
+			}
+			stalls := make([]stall, len(pt.cpus))
+			runToCompletion(t, session, 50000, func() {
+				for i, cpu := range pt.cpus {
+					// Закрепленный процесс остается на процессоре, пока не пройдут накладные расходы
+					if s := stalls[i]; s.remains > 0 && cpu.currentProcess != s.process {
+						t.Fatalf("такт %d: CPU%d сменил процесс до окончания накладных расходов", pt.tick, cpu.ID)
+					}
+					stalls[i] = stall{}
+					if cpu.pinned {
+						stalls[i] = stall{cpu.currentProcess, cpu.stallRemains}
+					}
+				}
+			})
+
+			switches, overhead := 0, 0
+			for _, cpu := range pt.cpus {
+				switches += cpu.ContextSwitches
+				overhead += cpu.OverheadTicks
+			}
+			if switches == 0 {
+				t.Fatal("не было ни одного переключения контекста")
+			}
+			if overhead != switches*test.cost {
+				t.Fatalf("накладные расходы %d тактов, ожидалось %d переключений * %d", overhead, switches, test.cost)
+			}
+		})
+	}
+}
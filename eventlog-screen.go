@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// eventLogScreen - экран журнала событий диспетчера и менеджера памяти
+type eventLogScreen struct {
+	list   *uitools.ListBox
+	layout *uitools.Layout
+	events *uitools.EventNode
+	// follow - список прокручивается к новым записям, пока пользователь не прокрутил его сам
+	follow  bool
+	message string
+}
+
+// newEventLogScreen создает экран журнала событий
+func newEventLogScreen() *eventLogScreen {
+	theme := uitools.CurrentTheme
+	s := &eventLogScreen{follow: true}
+
+	header := uitools.NewLabel(0, 0, fmt.Sprintf("%6s %5s %3s  %-13s %s", "Такт", "PID", "CPU", "Событие", "Подробности"), theme.Normal.Fg, theme.Normal.Bg)
+	s.list = uitools.NewListBox(0, 0, 0, 0, nil, theme.Normal.Fg, theme.Normal.Bg, nil)
+	s.layout = uitools.NewVerticalLayout(0).Add(header).AddStretch(s.list)
+
+	focus := uitools.NewFocusGroup(s.list)
+
+	s.events = uitools.NewEventNode()
+	s.events.AddWidget(focus)
+	s.events.On(uitools.KeyEvent, s.handleKey)
+	s.events.On(uitools.ResizeEvent, func(ev *termbox.Event) bool {
+		s.layout.Arrange(0, 2, ev.Width, ev.Height-3)
+		return false
+	})
+
+	s.layout.Arrange(0, 2, screenWidth, screenHeight-3)
+	return s
+}
+
+// handleKey включает слежение за новыми записями по 'f' и сохраняет журнал в файл по 'w'
+func (s *eventLogScreen) handleKey(ev *termbox.Event) bool {
+	switch ev.Ch {
+	case 'f':
+		s.follow = true
+	case 'w':
+		path := fmt.Sprintf("events-%d.jsonl", GetProcessTable().tick)
+		if err := GetEventLog().Save(path); err != nil {
+			s.message = err.Error()
+		} else {
+			s.message = "Журнал сохранен в " + path
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Title возвращает название экрана
+func (s *eventLogScreen) Title() string {
+	return "Журнал"
+}
+
+// Enter вызывается при переходе на экран
+func (s *eventLogScreen) Enter() {}
+
+// Leave вызывается при уходе с экрана
+func (s *eventLogScreen) Leave() {}
+
+// HandleEvent передает событие элементам экрана, прокрутка списка отключает слежение за новыми записями
+func (s *eventLogScreen) HandleEvent(ev *termbox.Event) bool {
+	if s.events.Dispatch(ev) {
+		if ev.Type == termbox.EventMouse || (ev.Type == termbox.EventKey && ev.Ch == 0) {
+			s.follow = false
+		}
+		return true
+	}
+
+	return false
+}
+
+// Draw отображает записи журнала
+func (s *eventLogScreen) Draw() {
+	theme := uitools.CurrentTheme
+	log := GetEventLog()
+
+	items := make([]string, len(log.Events))
+	for i, e := range log.Events {
+		items[i] = e.String()
+	}
+	s.list.SetItems(items)
+	if s.follow {
+		s.list.Scroll(len(items))
+	}
+
+	follow := "выкл"
+	if s.follow {
+		follow = "вкл"
+	}
+	uitools.Printf(0, 1, theme.Normal.Fg, theme.Normal.Bg, "Журнал событий: %d записей  Слежение: %s (f)  Сохранить: w", len(items), follow)
+	s.layout.Draw()
+
+	drawStatusBar()
+	if s.message != "" {
+		uitools.Print(statusBarX+1, statusBarY, theme.Status.Fg, theme.Status.Bg, s.message)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// EventType перечисляет действия диспетчера и менеджера памяти, попадающие в журнал
+type EventType int
+
+const (
+	// EventScheduled - поток процесса выбран для исполнения на процессоре
+	EventScheduled EventType = iota
+	// EventPreempted - квант истек до завершения потока
+	EventPreempted
+	// EventCompleted - процесс исполнил все такты
+	EventCompleted
+	// EventSwappedOut - сегмент процесса выгружен на диск
+	EventSwappedOut
+	// EventSwapFailed - выгрузка не удалась из-за переполнения диска
+	EventSwapFailed
+	// EventAllocated - процессу выделен сегмент оперативной памяти
+	EventAllocated
+	// EventFreed - сегмент процесса освобожден
+	EventFreed
+	// EventMergedHoles - освобожденный сегмент объединен с соседними пустыми
+	EventMergedHoles
+	// EventBlocked - процесс заблокирован
+	EventBlocked
+	// EventUnblocked - процесс разблокирован
+	EventUnblocked
+	// EventKilled - процесс завершен сигналом
+	EventKilled
+	// EventSuspended - процесс приостановлен
+	EventSuspended
+	// EventResumed - процесс возобновлен
+	EventResumed
+)
+
+// eventTypeNames - названия типов событий для панели журнала и ключи для файла журнала
+var eventTypeNames = map[EventType][2]string{
+	EventScheduled:   {"выбран", "scheduled"},
+	EventPreempted:   {"вытеснен", "preempted"},
+	EventCompleted:   {"завершен", "completed"},
+	EventSwappedOut:  {"выгружен", "swapped_out"},
+	EventSwapFailed:  {"сбой выгрузки", "swap_failed"},
+	EventAllocated:   {"память выдел.", "allocated"},
+	EventFreed:       {"память освоб.", "freed"},
+	EventMergedHoles: {"слияние дыр", "merged_holes"},
+	EventBlocked:     {"блокирован", "blocked"},
+	EventUnblocked:   {"разблокирован", "unblocked"},
+	EventKilled:      {"уничтожен", "killed"},
+	EventSuspended:   {"приостановлен", "suspended"},
+	EventResumed:     {"возобновлен", "resumed"},
+}
+
+// Stringify переводит вариант перечисления в строку
+func (et EventType) Stringify() string {
+	return eventTypeNames[et][0]
+}
+
+// MarshalText записывает тип события в файл журнала латинским ключом
+func (et EventType) MarshalText() ([]byte, error) {
+	return []byte(eventTypeNames[et][1]), nil
+}
+
+// Event - запись журнала: такт, процесс, процессор и подробности действия
+type Event struct {
+	Tick int       `json:"tick"`
+	PID  int       `json:"pid"`
+	CPU  int       `json:"cpu"`
+	Type EventType `json:"type"`
+	// Details - параметры действия, например адрес и размер сегмента
+	Details string `json:"details,omitempty"`
+}
+
+// String форматирует запись для панели журнала
+func (e Event) String() string {
+	pid, cpu := "-", "-"
+	if e.PID != -1 {
+		pid = fmt.Sprint(e.PID)
+	}
+	if e.CPU != -1 {
+		cpu = fmt.Sprint(e.CPU)
+	}
+
+	return fmt.Sprintf("%6d %5s %3s  %-13s %s", e.Tick, pid, cpu, e.Type.Stringify(), e.Details)
+}
+
+// eventLogLength - число последних записей, хранимых в памяти для панели журнала
+const eventLogLength = 2000
+
+// EventLog - журнал действий диспетчера и менеджера памяти
+// Последние записи хранятся в памяти, все записи могут дописываться в файл по одной JSON-строке на событие
+type EventLog struct {
+	Events []Event
+	output io.WriteCloser
+	// repeated - записываются события повторного прогона уже пройденных тактов, они не дописываются в файл
+	repeated bool
+}
+
+// Log добавляет запись в журнал, PID и CPU равны -1, если действие не относится к процессу или процессору
+func (log *EventLog) Log(eventType EventType, pid, cpu int, format string, a ...interface{}) {
+	e := Event{Tick: GetProcessTable().tick, PID: pid, CPU: cpu, Type: eventType, Details: fmt.Sprintf(format, a...)}
+
+	log.Events = append(log.Events, e)
+	if len(log.Events) > eventLogLength {
+		log.Events = log.Events[1:]
+	}
+
+	if log.output != nil && !log.repeated {
+		writeEvent(log.output, e)
+	}
+}
+
+// writeEvent записывает событие JSON-строкой
+func writeEvent(w io.Writer, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Save записывает хранимые в памяти записи в новый файл
+func (log *EventLog) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range log.Events {
+		if err := writeEvent(file, e); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	return file.Close()
+}
+
+// Open начинает дописывать записи журнала в файл
+func (log *EventLog) Open(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	log.Close()
+	log.output = file
+	return nil
+}
+
+// Close прекращает запись журнала в файл
+func (log *EventLog) Close() error {
+	if log.output == nil {
+		return nil
+	}
+
+	err := log.output.Close()
+	log.output = nil
+	return err
+}
+
+var eventLogOnce sync.Once
+var eventLogInstance *EventLog
+
+// GetEventLog предоставляет единственный экземпляр журнала событий
+func GetEventLog() *EventLog {
+	eventLogOnce.Do(func() {
+		eventLogInstance = &EventLog{}
+	})
+
+	return eventLogInstance
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProcessStats - итоговые показатели процесса для выгрузки
+type ProcessStats struct {
+	PID    int    `json:"pid"`
+	Name   string `json:"name"`
+	Memory int    `json:"memory"`
+	// Arrival и Completion - такты создания и завершения, Completion равен -1 у незавершенного процесса
+	Arrival    int `json:"arrival"`
+	Completion int `json:"completion"`
+	CPUTime    int `json:"cpu_time"`
+	// WaitTicks - такты в очереди готовых процессов
+	WaitTicks int `json:"wait_ticks"`
+	Swaps     int `json:"swaps"`
+}
+
+// processStatsHeader - заголовок таблицы показателей процессов в формате CSV
+var processStatsHeader = []string{"pid", "name", "memory", "arrival", "completion", "cpu_time", "wait_ticks", "swaps"}
+
+// ProcessStats возвращает показатели завершенных и исполняемых пользовательских процессов в порядке PID
+func (pt *ProcessTable) ProcessStats() []ProcessStats {
+	stats := []ProcessStats{}
+	for _, list := range [][]*Process{pt.finished, pt.table} {
+		for _, p := range list {
+			if p.GID == 1 {
+				continue
+			}
+			stats = append(stats, ProcessStats{PID: p.PID, Name: p.Name, Memory: p.Memory,
+				Arrival: p.Arrival, Completion: p.Completion, CPUTime: p.CPUTime, WaitTicks: p.WaitTicks, Swaps: p.Swaps})
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].PID < stats[j].PID })
+	return stats
+}
+
+// row формирует строку CSV показателей процесса
+func (s ProcessStats) row() []string {
+	return []string{strconv.Itoa(s.PID), s.Name, strconv.Itoa(s.Memory), strconv.Itoa(s.Arrival), strconv.Itoa(s.Completion),
+		strconv.Itoa(s.CPUTime), strconv.Itoa(s.WaitTicks), strconv.Itoa(s.Swaps)}
+}
+
+// tickStatsHeader формирует заголовок таблицы показателей тактов в формате CSV, по столбцу на каждый процессор
+func tickStatsHeader(cpus int) []string {
+	header := []string{"tick"}
+	for i := 0; i < cpus; i++ {
+		header = append(header, fmt.Sprintf("cpu%d_pid", i))
+	}
+
+	return append(header, "cpu_utilization", "occupied_ram", "occupied_disk", "ready", "blocked",
+		"holes", "largest_hole", "external_fragmentation", "failed_allocations")
+}
+
+// row формирует строку CSV показателей такта
+func (s TickSample) row() []string {
+	row := []string{strconv.Itoa(s.Tick)}
+	for _, pid := range s.Running {
+		row = append(row, strconv.Itoa(pid))
+	}
+
+	return append(row, strconv.FormatFloat(s.CPUUtilization, 'f', 1, 64), strconv.Itoa(s.OccupiedRAM), strconv.Itoa(s.OccupiedDisk),
+		strconv.Itoa(s.Ready), strconv.Itoa(s.Blocked),
+		strconv.Itoa(s.Holes), strconv.Itoa(s.LargestHole), strconv.FormatFloat(s.ExternalFragmentation, 'f', 3, 64), strconv.Itoa(s.FailedAllocations))
+}
+
+// writeCSV записывает таблицу с заголовком в новый файл
+func writeCSV(path string, header []string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// DroppedSamples возвращает число первых тактов, показатели которых уже отброшены из истории
+func (pt *ProcessTable) DroppedSamples() int {
+	if len(pt.Samples) == 0 {
+		return pt.tick
+	}
+
+	// Показатели записываются после увеличения номера такта, поэтому первая запись относится к такту 1
+	return pt.Samples[0].Tick - 1
+}
+
+// exportSummary формирует сообщение о выгрузке статистики в файлы paths
+// и предупреждает, если в выгрузку попали не все такты
+func exportSummary(paths []string) string {
+	message := "Статистика выгружена в " + strings.Join(paths, ", ")
+	if dropped := GetProcessTable().DroppedSamples(); dropped > 0 {
+		message += fmt.Sprintf("; показатели первых %d тактов не выгружены, для полной выгрузки включите KeepAllSamples", dropped)
+	}
+
+	return message
+}
+
+// ExportStats выгружает показатели процессов и тактов в файлы prefix-processes.csv, prefix-ticks.csv и prefix.json
+// Показатели тактов берутся из истории, хранящей последние sampleHistoryLength тактов или все такты при KeepAllSamples
+// Возвращает имена записанных файлов
+func ExportStats(prefix string) ([]string, error) {
+	pt := GetProcessTable()
+	processes := pt.ProcessStats()
+
+	processLines := make([][]string, len(processes))
+	for i, s := range processes {
+		processLines[i] = s.row()
+	}
+	tickLines := make([][]string, len(pt.Samples))
+	for i, s := range pt.Samples {
+		tickLines[i] = s.row()
+	}
+
+	paths := []string{prefix + "-processes.csv", prefix + "-ticks.csv", prefix + ".json"}
+	if err := writeCSV(paths[0], processStatsHeader, processLines); err != nil {
+		return nil, err
+	}
+	if err := writeCSV(paths[1], tickStatsHeader(len(pt.cpus)), tickLines); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Processes []ProcessStats `json:"processes"`
+		Ticks     []TickSample   `json:"ticks"`
+	}{processes, pt.Samples}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(paths[2], data, 0644); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
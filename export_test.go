@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// readCSV читает таблицу из файла CSV вместе с заголовком
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func TestExportStats(t *testing.T) {
+	const ticks = 40
+
+	session := startModel(t, 2, nil)
+	if err := session.Do(Action{Kind: ActionCreateRandom, Value: 5}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < ticks; i++ {
+		session.Step()
+	}
+
+	prefix := filepath.Join(t.TempDir(), "stats")
+	paths, err := ExportStats(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{prefix + "-processes.csv", prefix + "-ticks.csv", prefix + ".json"}; strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("записаны файлы %v, ожидались %v", paths, want)
+	}
+
+	processes := readCSV(t, paths[0])
+	if header := strings.Join(processes[0], ","); header != "pid,name,memory,arrival,completion,cpu_time,wait_ticks,swaps" {
+		t.Fatalf("заголовок показателей процессов: %s", header)
+	}
+	if len(processes) != 1+5 {
+		t.Fatalf("строк показателей процессов %d, ожидалось 5", len(processes)-1)
+	}
+
+	tickRows := readCSV(t, paths[1])
+	header := "tick,cpu0_pid,cpu1_pid,cpu_utilization,occupied_ram,occupied_disk,ready,blocked," +
+		"holes,largest_hole,external_fragmentation,failed_allocations"
+	if got := strings.Join(tickRows[0], ","); got != header {
+		t.Fatalf("заголовок показателей тактов: %s", got)
+	}
+	if len(tickRows) != 1+ticks {
+		t.Fatalf("строк показателей тактов %d, ожидалось %d", len(tickRows)-1, ticks)
+	}
+	for i, row := range tickRows[1:] {
+		if len(row) != len(tickRows[0]) {
+			t.Fatalf("строка %d: %d столбцов, ожидалось %d", i, len(row), len(tickRows[0]))
+		}
+		if row[0] != strconv.Itoa(i+1) {
+			t.Fatalf("строка %d относится к такту %s, ожидался %d", i, row[0], i+1)
+		}
+	}
+
+	data, err := os.ReadFile(paths[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Processes []map[string]interface{} `json:"processes"`
+		Ticks     []map[string]interface{} `json:"ticks"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Processes) != 5 || len(report.Ticks) != ticks {
+		t.Fatalf("в JSON %d процессов и %d тактов, ожидалось 5 и %d", len(report.Processes), len(report.Ticks), ticks)
+	}
+	for _, key := range processes[0] {
+		if _, ok := report.Processes[0][key]; !ok {
+			t.Fatalf("в JSON процесса нет поля %s", key)
+		}
+	}
+	for _, key := range []string{"tick", "holes", "largest_hole", "external_fragmentation", "failed_allocations"} {
+		if _, ok := report.Ticks[0][key]; !ok {
+			t.Fatalf("в JSON такта нет поля %s", key)
+		}
+	}
+}
+
+func TestKeepAllSamples(t *testing.T) {
+	const ticks = sampleHistoryLength + 20
+
+	tests := []struct {
+		keep    bool
+		samples int
+		dropped int
+	}{
+		{false, sampleHistoryLength, 20},
+		{true, ticks, 0},
+	}
+
+	for _, test := range tests {
+		t.Run("KeepAllSamples "+strconv.FormatBool(test.keep), func(t *testing.T) {
+			session := startModel(t, 3, func(c *Config) { c.KeepAllSamples = test.keep })
+			for i := 0; i < ticks; i++ {
+				session.Step()
+			}
+
+			pt := GetProcessTable()
+			if len(pt.Samples) != test.samples {
+				t.Fatalf("в истории %d тактов, ожидалось %d", len(pt.Samples), test.samples)
+			}
+			if dropped := pt.DroppedSamples(); dropped != test.dropped {
+				t.Fatalf("DroppedSamples() = %d, ожидалось %d", dropped, test.dropped)
+			}
+			if warned := strings.Contains(exportSummary(nil), "KeepAllSamples"); warned != (test.dropped > 0) {
+				t.Fatalf("предупреждение о неполной выгрузке: %v, ожидалось %v", warned, test.dropped > 0)
+			}
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"flag"
 	"log"
-	"math/rand"
+	"os"
 	"time"
 
 	"operating-systems/processes/uitools"
@@ -10,200 +11,169 @@ import (
 	"github.com/nsf/termbox-go"
 )
 
-// TODO:
-// Отрисовка таблицы процессов
+func main() {
+	configPath := flag.String("config", "", "путь к файлу конфигурации в формате JSON")
+	recordPath := flag.String("record", "", "файл, в который записывается сеанс")
+	replayPath := flag.String("replay", "", "файл записанного сеанса для воспроизведения")
+	seed := flag.Int64("seed", 0, "начальное значение генератора случайных чисел, 0 - по времени запуска")
+	restorePath := flag.String("restore", "", "файл снимка, с состояния которого начинается работа модели")
+	compare := flag.Bool("compare", false, "сравнить конфигурации на одной нагрузке и вывести таблицу без запуска интерфейса")
+	policies := flag.String("policies", "", "сравниваемые конфигурации вида shared/first-fit,percpu/best-fit, по умолчанию все")
+	workload := flag.Int("workload", 200, "число процессов нагрузки для сравнения")
+	maxTicks := flag.Int("ticks", 20000, "наибольшая длительность прогона нагрузки в тактах")
+	headless := flag.Bool("headless", false, "выполнить команды консоли из стандартного ввода без запуска интерфейса")
+	flag.Parse()
+
+	if *configPath != "" {
+		if err := LoadConfig(*configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
 
-// UIStateEnum перечисляет все состояния графического интерфейса
-type UIStateEnum int
+	// Сравнение конфигураций выполняется без интерфейса, результат выводится в стандартный вывод
+	if *compare {
+		list, err := ParsePolicies(*policies)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := RunComparison(os.Stdout, list, *seed, *workload, *maxTicks); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-const (
-	// ProcessMonitor указывает, что отображается диспетчер задач
-	ProcessMonitor UIStateEnum = iota
-	// MemoryDispatchMonitor указывает, что отображается менеджер памяти
-	MemoryDispatchMonitor
-)
+	// Воспроизводимый сеанс задает конфигурацию и генератор случайных чисел, записываемый - запоминает их
+	session := GetSession()
+	if *replayPath != "" && *restorePath != "" {
+		log.Fatal("снимок восстанавливается воспроизводимым сеансом, -restore и -replay несовместимы")
+	}
+	if *replayPath != "" {
+		if err := session.LoadSession(*replayPath); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		session.Record(*seed, *recordPath)
+		session.Snapshot = *restorePath
+		defer func() {
+			if err := session.Save(); err != nil {
+				log.Print(err)
+			}
+		}()
+	}
+
+	if path := GetConfig().EventLog; path != "" {
+		if err := GetEventLog().Open(path); err != nil {
+			log.Fatal(err)
+		}
+		defer GetEventLog().Close()
+	}
+
+	// Сценарий команд выполняется без интерфейса: модель продвигается только командами step и run
+	if *headless {
+		if err := InitDispatcher(); err != nil {
+			log.Fatal(err)
+		}
+		if err := session.Begin(); err != nil {
+			log.Fatal(err)
+		}
+		if err := RunScript(os.Stdin, os.Stdout); err != nil {
+			log.Print(err)
+		}
+		return
+	}
 
-func main() {
 	// ==== Инициализация ресурсов библиотеки псевдографики ==== //
 	if err := initTermbox(); err != nil {
 		log.Fatal(err)
 	}
 	defer termbox.Close()
 
+	if err := applyTheme(); err != nil {
+		termbox.Close()
+		log.Fatal(err)
+	}
+	theme := uitools.CurrentTheme
+
 	// =============== Рабочие переменные ============== //
 	// Флаг завершения работы - нужен для завершения программного цикла
 	isQuitEvent := false
-	// Текущий экран графического интерфейса
-	uiState := ProcessMonitor
-	// Индекс выделенного процесса
-	selectedProcessIndex := -1
-	// Координаты мыши
-	hoverX, hoverY := 0, 0
 
 	// ========== Инициализация состояния модели =========== //
-	// Экземпляр таблицы процессов
-	processTable := GetProcessTable()
-	// Экземпляр менеджера памяти
-	memoryManagementUnit := GetMMU()
-	InitDispatcher()
-
-	rand.Seed(time.Now().Unix())
-
-	// ========== Инициализация элементов управления =========== //
-	createProcessButton := uitools.NewButton(1, 1, "Создать процесс", termbox.ColorWhite, termbox.ColorBlue,
-		func(b *uitools.Button) {
-			processTable.AddProcess()
-		})
-
-	blockProcessButton := uitools.NewButton(20, 1, "Блокировать процесс    ", termbox.ColorWhite, termbox.ColorBlue,
-		func(b *uitools.Button) {
-			if selectedProcessIndex != -1 && len(processTable.table) > selectedProcessIndex {
-				// Проверка, что процесс - пользовательский
-				if processTable.table[selectedProcessIndex].GID != 1 {
-					processTable.table[selectedProcessIndex].State = Blocking
-				}
-			}
-		})
-
-	unblockProcessButton := uitools.NewButton(47, 1, "Разблокировать процесс    ", termbox.ColorWhite, termbox.ColorBlue,
-		func(b *uitools.Button) {
-			if selectedProcessIndex != -1 && len(processTable.table) > selectedProcessIndex {
-				if processTable.table[selectedProcessIndex].State == Blocking {
-					processTable.table[selectedProcessIndex].State = Readiness
-					// Если процесс блокировался, то он добавляется в очередь следующим для исполнения
-				}
-			}
-		})
-
-	for {
-		// ======== Выполнение действий по нажатию ЛКМ, Escape ======== //
-		pollEvents(
-			func(ev *termbox.Event) {
-				switch uiState {
-				case ProcessMonitor:
-					createProcessButton.CheckClick(ev.MouseX, ev.MouseY)
-					blockProcessButton.CheckClick(ev.MouseX, ev.MouseY)
-					unblockProcessButton.CheckClick(ev.MouseX, ev.MouseY)
-
-					// Выбор элемента из таблицы процессов
-					if ev.MouseX >= 0 && ev.MouseX <= 63 && ev.MouseY >= 8 && ev.MouseY <= 28 {
-						if ev.MouseY%2 == 0 && len(processTable.table) > (ev.MouseY-8)/2 {
-							selectedProcessIndex = (ev.MouseY-8)/2 + processTable.first
-						}
-					} else {
-						selectedProcessIndex = -1
-					}
-				case MemoryDispatchMonitor:
-
-				}
-
-			},
-			func(ev *termbox.Event) {
-				if uiState == ProcessMonitor {
-					switch ev.Key {
-					case termbox.MouseWheelUp:
-						if processTable.first > 0 {
-							processTable.first--
-						}
-					case termbox.MouseWheelDown:
-						if len(processTable.table)-processTable.first > 11 {
-							processTable.first++
-						}
-					}
-				} else {
-					hoverX, hoverY = ev.MouseX, ev.MouseY
-				}
-			},
-			func(ev *termbox.Event) {
-				isQuitEvent = true
-			},
-			func(ev *termbox.Event) {
-				switch ev.Key {
-				case termbox.KeyF2:
-					uiState = ProcessMonitor
-				case termbox.KeyF4:
-					uiState = MemoryDispatchMonitor
-				case termbox.KeyArrowUp:
-					if processTable.first > 0 && uiState == ProcessMonitor {
-						processTable.first--
-					}
-				case termbox.KeyArrowDown:
-					if len(processTable.table)-processTable.first > 11 && uiState == ProcessMonitor {
-						processTable.first++
-					}
-				}
-			})
+	if err := InitDispatcher(); err != nil {
+		termbox.Close()
+		log.Fatal(err)
+	}
+	if err := session.Begin(); err != nil {
+		termbox.Close()
+		log.Fatal(err)
+	}
+	resizeScreen(termbox.Size())
+
+	// ========== Экраны приложения =========== //
+	// Экраны переключаются функциональными клавишами, строка меню занимает первую строку экрана
+	screens := uitools.NewScreenManager()
+	screens.Add(termbox.KeyF2, "F2", newProcessScreen())
+	screens.Add(termbox.KeyF3, "F3", newDashboardScreen())
+	screens.Add(termbox.KeyF4, "F4", newMemoryScreen())
+	screens.Add(termbox.KeyF5, "F5", &bankerScreen{})
+	screens.Add(termbox.KeyF6, "F6", &statisticsScreen{})
+	screens.Add(termbox.KeyF7, "F7", newSettingsScreen())
+	screens.Add(termbox.KeyF8, "F8", newEventLogScreen())
+
+	// ========== Маршрутизация событий =========== //
+	// Событие сначала получает открытый экран, затем оно всплывает к общим обработчикам
+	router := uitools.NewEventNode()
+	router.On(uitools.ResizeEvent, func(ev *termbox.Event) bool {
+		resizeScreen(ev.Width, ev.Height)
+		return false
+	})
+	// Открытая консоль команд получает события раньше экранов
+	console := &commandConsole{}
+	router.AddWidget(console).On(uitools.ResizeEvent, console.handleResize)
+	router.AddWidget(screens).On(uitools.ResizeEvent, screens.HandleEvent)
+
+	router.On(uitools.KeyEvent, func(ev *termbox.Event) bool {
+		if ev.Key != termbox.KeyEsc {
+			return false
+		}
+		isQuitEvent = true
+		return true
+	})
+	router.On(uitools.KeyEvent, handleSessionKey)
+	router.On(uitools.KeyEvent, func(ev *termbox.Event) bool {
+		if ev.Ch != ':' {
+			return false
+		}
+		console.Open()
+		return true
+	})
+
+	// ====================== Логика модели ====================== //
+	// Такт завершает исполнение процессов, выбранных на прошлом такте, и выбирает новые,
+	// поэтому между тактами на экране видны исполняемые процессы
+	router.On(uitools.TickEvent, func(ev *termbox.Event) bool {
+		if !session.Paused {
+			session.Step()
+		}
+		return true
+	})
+
+	startTicker(time.Duration(GetConfig().TickInterval) * time.Millisecond)
+
+	for !isQuitEvent {
+		ev := uitools.PollEvent()
+		router.Dispatch(&ev)
 		if isQuitEvent {
 			break
 		}
 
-		// ====================== Логика модели ====================== //
-		ScheduleProcess()
-
 		// ================= Отрисовка псевдографики ================= //
 		drawGUI(func() {
-			switch uiState {
-			case ProcessMonitor:
-				createProcessButton.Draw()
-				blockProcessButton.Draw()
-				unblockProcessButton.Draw()
-
-				if selectedProcessIndex != -1 {
-					uitools.Printf(40, 2, termbox.ColorBlue, termbox.ColorWhite, "%3d", selectedProcessIndex)
-					uitools.Printf(70, 2, termbox.ColorBlue, termbox.ColorWhite, "%3d", selectedProcessIndex)
-				}
-
-				processTable.Draw(0, 4)
-
-				termbox.SetCell(80, processTable.roundRobinProcessIndex*2+7, '<', termbox.ColorBlue, termbox.ColorWhite)
-
-				drawStatusBar()
-
-			case MemoryDispatchMonitor:
-				blockType := MemHole
-				blockPos := -1
-				blockSize := -1
-
-				uitools.Print(0, 0, termbox.ColorWhite, termbox.ColorBlue, "Менеджер ресурсов")
-
-				for i, e := 0, memoryManagementUnit.blockList.Front(); e != nil; i, e = i+1, e.Next() {
-					v := e.Value.(*MemoryBlockNode)
-
-					var blockSymbol rune
-					if v.NodeType == MemProcess {
-						blockSymbol = '▓'
-					} else {
-						blockSymbol = '░'
-					}
-
-					termbox.SetCell(1+(i%40)*2, 2+(i/40)*2, blockSymbol, termbox.ColorWhite, termbox.ColorBlue)
-					if hoverX == 2+(i%40)*2 && hoverY == 3+(i/40)*2 {
-						blockType = v.NodeType
-						blockPos = v.Position
-						blockSize = v.Size
-					}
-
-					if i != memoryManagementUnit.blockList.Len()-1 {
-						termbox.SetCell(2+(i%40)*2, 2+(i/40)*2, '→', termbox.ColorWhite, termbox.ColorBlue)
-					}
-				}
-
-				drawStatusBar()
-				if blockPos != -1 {
-					var btString string
-					if blockType == MemHole {
-						btString = "Пустой сегмент"
-					} else {
-						btString = "Сегмент процесса"
-					}
-					uitools.Printf(statusBarX+1, statusBarY, termbox.ColorBlue, termbox.ColorWhite, "%s Начало: %d Размер: %d", btString, blockPos, blockSize)
-				}
-			}
-		},
-			termbox.ColorBlue,
-			termbox.ColorBlue)
-
-		// ====================== Логика модели ====================== //
-		PerformProcess()
+			screens.Draw()
+			console.Draw()
+		}, theme.Normal.Fg, theme.Normal.Bg)
 	}
 }
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Размещение карты памяти на экране: карта начинается под заголовком и строкой масштаба,
+// под картой остаются строки показателей фрагментации и легенды
+const (
+	memoryMapX           = 1
+	memoryMapY           = 3
+	memoryMapMetricsRows = 5
+	memoryMapLegendRows  = 3
+	// metricsCaptionWidth - ширина подписи показателя перед мини-графиком
+	metricsCaptionWidth = 36
+)
+
+// memoryScreen - экран менеджера памяти: карта оперативной памяти в масштабе, показатели фрагментации
+// и подсказка по ячейке под указателем мыши
+type memoryScreen struct {
+	hoverX, hoverY int
+	sparklines     []*uitools.Sparkline
+}
+
+// fragmentationMetrics - показатели фрагментации, отображаемые мини-графиками: подпись и значение показателя
+// Для неудачных выделений график показывает число неудач на каждом такте
+var fragmentationMetrics = []struct {
+	caption string
+	value   func(f, prev Fragmentation) float64
+}{
+	{"Внешняя фрагментация, %", func(f, prev Fragmentation) float64 { return f.External * 100 }},
+	{"Пустых сегментов", func(f, prev Fragmentation) float64 { return float64(f.Holes) }},
+	{"Средний пустой сегмент", func(f, prev Fragmentation) float64 { return f.MeanHole }},
+	{"Неудачных выделений за такт", func(f, prev Fragmentation) float64 { return float64(f.FailedAllocations - prev.FailedAllocations) }},
+}
+
+// memoryMap - геометрия карты памяти: каждая ячейка представляет bytesPerCell байт подряд
+type memoryMap struct {
+	columns, rows int
+	bytesPerCell  int
+}
+
+// cellSegment - сегмент, попадающий в ячейку карты, и число его байт в ячейке
+type cellSegment struct {
+	block   *MemoryBlockNode
+	overlap int
+}
+
+// newMemoryScreen создает экран менеджера памяти
+func newMemoryScreen() *memoryScreen {
+	theme := uitools.CurrentTheme
+	s := &memoryScreen{hoverX: -1, hoverY: -1}
+	for range fragmentationMetrics {
+		s.sparklines = append(s.sparklines, uitools.NewSparkline(0, 0, 0, theme.Normal.Fg, theme.Normal.Bg))
+	}
+
+	return s
+}
+
+// newMemoryMap рассчитывает масштаб карты так, чтобы вся оперативная память поместилась на экране
+func newMemoryMap() memoryMap {
+	m := memoryMap{columns: screenWidth - 2*memoryMapX, rows: statusBarY - 2 - memoryMapMetricsRows - memoryMapLegendRows - memoryMapY}
+	if m.columns < 1 {
+		m.columns = 1
+	}
+	if m.rows < 1 {
+		m.rows = 1
+	}
+
+	cells := m.columns * m.rows
+	m.bytesPerCell = (MaxRAM + cells - 1) / cells
+
+	return m
+}
+
+// cells возвращает число ячеек, занятых оперативной памятью
+func (m memoryMap) cells() int {
+	return (MaxRAM + m.bytesPerCell - 1) / m.bytesPerCell
+}
+
+// cellAt возвращает номер ячейки под экранными координатами или -1
+func (m memoryMap) cellAt(x, y int) int {
+	column, row := x-memoryMapX, y-memoryMapY
+	if column < 0 || column >= m.columns || row < 0 || row >= m.rows {
+		return -1
+	}
+
+	if cell := row*m.columns + column; cell < m.cells() {
+		return cell
+	}
+	return -1
+}
+
+// segments возвращает сегменты, пересекающие ячейку, начиная поиск с элемента списка from
+// и элемент, с которого следует искать сегменты следующей ячейки
+func (m memoryMap) segments(cell int, from *list.Element) ([]cellSegment, *list.Element) {
+	start := cell * m.bytesPerCell
+	end := start + m.bytesPerCell
+	if end > MaxRAM {
+		end = MaxRAM
+	}
+
+	// Пропуск сегментов, закончившихся до начала ячейки
+	for from != nil {
+		v := from.Value.(*MemoryBlockNode)
+		if v.Position+v.Size > start {
+			break
+		}
+		from = from.Next()
+	}
+
+	var result []cellSegment
+	for e := from; e != nil; e = e.Next() {
+		v := e.Value.(*MemoryBlockNode)
+		if v.Position >= end {
+			break
+		}
+
+		left, right := v.Position, v.Position+v.Size
+		if left < start {
+			left = start
+		}
+		if right > end {
+			right = end
+		}
+		result = append(result, cellSegment{v, right - left})
+	}
+
+	return result, from
+}
+
+// dominant возвращает сегмент, занимающий наибольшую часть ячейки
+func dominant(segments []cellSegment) *MemoryBlockNode {
+	var best cellSegment
+	for _, s := range segments {
+		if s.overlap > best.overlap {
+			best = s
+		}
+	}
+
+	return best.block
+}
+
+// blockOwners сопоставляет сегментам оперативной памяти владеющие ими процессы
+func blockOwners() map[*MemoryBlockNode]*Process {
+	owners := map[*MemoryBlockNode]*Process{}
+	for _, p := range GetProcessTable().table {
+		if p.MemoryBlock != nil {
+			owners[p.MemoryBlock] = p
+		}
+	}
+
+	return owners
+}
+
+// Title возвращает название экрана
+func (s *memoryScreen) Title() string {
+	return "Память"
+}
+
+// Enter вызывается при переходе на экран
+func (s *memoryScreen) Enter() {}
+
+// Leave сбрасывает координаты мыши, чтобы подсказка не осталась от прошлого посещения
+func (s *memoryScreen) Leave() {
+	s.hoverX, s.hoverY = -1, -1
+}
+
+// HandleEvent запоминает координаты мыши
+func (s *memoryScreen) HandleEvent(ev *termbox.Event) bool {
+	if ev.Type != termbox.EventMouse {
+		return false
+	}
+
+	s.hoverX, s.hoverY = ev.MouseX, ev.MouseY
+	return true
+}
+
+// Draw отображает карту памяти, легенду и сведения о ячейке под указателем мыши
+// Ячейка окрашивается цветом процесса, занимающего большую ее часть, ячейки с несколькими сегментами отмечаются штриховкой
+func (s *memoryScreen) Draw() {
+	theme := uitools.CurrentTheme
+	mmu := GetMMU()
+	owners := blockOwners()
+	m := newMemoryMap()
+
+	uitools.Print(0, 1, theme.Normal.Fg, theme.Normal.Bg, "Менеджер памяти")
+	uitools.Printf(0, 2, theme.Normal.Fg, theme.Normal.Bg, "1 ячейка = %d байт  Занято: %d из %d  Сегментов: %d",
+		m.bytesPerCell, mmu.OccupiedRAM, MaxRAM, mmu.blockList.Len())
+
+	hovered := m.cellAt(s.hoverX, s.hoverY)
+	var hoveredSegments []cellSegment
+
+	from := mmu.blockList.Front()
+	for cell := 0; cell < m.cells(); cell++ {
+		var segments []cellSegment
+		segments, from = m.segments(cell, from)
+		if cell == hovered {
+			hoveredSegments = segments
+		}
+
+		block := dominant(segments)
+		style, symbol := theme.Normal, '░'
+		if p := owners[block]; p != nil {
+			style, symbol = theme.PaletteStyle(p.PID), '█'
+		}
+		if len(segments) > 1 {
+			symbol = '▒'
+		}
+
+		termbox.SetCell(memoryMapX+cell%m.columns, memoryMapY+cell/m.columns, symbol, style.Fg, style.Bg)
+	}
+
+	s.drawMetrics(m)
+	s.drawLegend(owners, m)
+
+	drawStatusBar()
+	if hovered != -1 {
+		s.drawTooltip(hovered, hoveredSegments, owners, m)
+	}
+}
+
+// drawMetrics выводит под картой текущие показатели фрагментации и их историю
+func (s *memoryScreen) drawMetrics(m memoryMap) {
+	theme := uitools.CurrentTheme
+	mmu := GetMMU()
+	top := memoryMapY + m.rows + 1
+	current := mmu.Fragmentation()
+
+	uitools.Printf(memoryMapX, top, theme.Normal.Fg, theme.Normal.Bg, "Наибольший пустой сегмент: %d из %d свободных  Неудачных выделений: %d",
+		current.LargestHole, current.FreeRAM, current.FailedAllocations)
+
+	for i, metric := range fragmentationMetrics {
+		values := make([]float64, len(mmu.History))
+		for j, f := range mmu.History {
+			prev := f
+			if j > 0 {
+				prev = mmu.History[j-1]
+			}
+			values[j] = metric.value(f, prev)
+		}
+
+		latest := 0.0
+		if len(values) > 0 {
+			latest = values[len(values)-1]
+		}
+
+		y := top + 1 + i
+		uitools.Printf(memoryMapX, y, theme.Normal.Fg, theme.Normal.Bg, "%-28s %7.1f", metric.caption, latest)
+		s.sparklines[i].SetPosition(memoryMapX+metricsCaptionWidth+1, y)
+		s.sparklines[i].SetSize(screenWidth-2*memoryMapX-metricsCaptionWidth-1, 1)
+		s.sparklines[i].SetValues(values)
+		s.sparklines[i].Draw()
+	}
+}
+
+// drawLegend выводит под картой цвета процессов в порядке расположения их сегментов
+func (s *memoryScreen) drawLegend(owners map[*MemoryBlockNode]*Process, m memoryMap) {
+	theme := uitools.CurrentTheme
+	top := memoryMapY + m.rows + 1 + memoryMapMetricsRows + 1
+
+	var items []string
+	var styles []uitools.Style
+	items = append(items, "░ пусто")
+	styles = append(styles, theme.Normal)
+	for e := GetMMU().blockList.Front(); e != nil; e = e.Next() {
+		if p := owners[e.Value.(*MemoryBlockNode)]; p != nil {
+			items = append(items, fmt.Sprintf("█ %d %s (%d)", p.PID, p.Name, p.Memory))
+			styles = append(styles, theme.PaletteStyle(p.PID))
+		}
+	}
+
+	x, y := memoryMapX, top
+	for i, item := range items {
+		width := len([]rune(item))
+
+		// На последней строке легенды остается место для числа невместившихся процессов
+		reserve := 16
+		if i == len(items)-1 {
+			reserve = 0
+		}
+		if y == top+memoryMapLegendRows-1 && x+width+reserve >= screenWidth {
+			uitools.Printf(x, y, theme.Normal.Fg, theme.Normal.Bg, "... еще %d", len(items)-i)
+			return
+		}
+
+		if x+width >= screenWidth {
+			x, y = memoryMapX, y+1
+		}
+
+		uitools.Print(x, y, styles[i].Fg, styles[i].Bg, item)
+		x += width + 2
+	}
+}
+
+// drawTooltip выводит в строке состояния адреса ячейки и сегмент, занимающий большую ее часть
+func (s *memoryScreen) drawTooltip(cell int, segments []cellSegment, owners map[*MemoryBlockNode]*Process, m memoryMap) {
+	theme := uitools.CurrentTheme
+
+	start := cell * m.bytesPerCell
+	end := start + m.bytesPerCell
+	if end > MaxRAM {
+		end = MaxRAM
+	}
+
+	text := fmt.Sprintf("Адреса %d-%d", start, end-1)
+	if block := dominant(segments); block != nil {
+		owner := "пустой сегмент"
+		if p := owners[block]; p != nil {
+			owner = fmt.Sprintf("процесс %d %s", p.PID, p.Name)
+		} else if block.NodeType == MemProcess {
+			owner = "сегмент процесса"
+		}
+		text += fmt.Sprintf("  %s: начало %d, размер %d", owner, block.Position, block.Size)
+	}
+	if len(segments) > 1 {
+		text += fmt.Sprintf("  (сегментов в ячейке: %d)", len(segments))
+	}
+
+	uitools.Print(statusBarX+1, statusBarY, theme.Status.Fg, theme.Status.Bg, text)
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"container/list"
+	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -10,8 +12,34 @@ const (
 	MaxRAM = 4194304
 	// MaxDiskSpace - 256 мегабайт
 	MaxDiskSpace = 67108864
+	// memoryHistoryLength - число последних тактов, для которых хранятся показатели фрагментации
+	memoryHistoryLength = 256
 )
 
+// Стратегии размещения сегментов в оперативной памяти
+const (
+	// FirstFit - первый подходящий пустой сегмент
+	FirstFit = "first-fit"
+	// BestFit - наименьший подходящий пустой сегмент
+	BestFit = "best-fit"
+	// WorstFit - наибольший пустой сегмент
+	WorstFit = "worst-fit"
+)
+
+// placements - доступные стратегии размещения
+var placements = []string{FirstFit, BestFit, WorstFit}
+
+// checkPlacement проверяет, что стратегия размещения существует
+func checkPlacement(placement string) error {
+	for _, p := range placements {
+		if p == placement {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("неизвестная стратегия размещения %q, доступны: %s", placement, strings.Join(placements, ", "))
+}
+
 // MemoryBlockNodeType представляет тип сегмента в связном списке блоков памяти
 type MemoryBlockNodeType int
 
@@ -33,40 +61,119 @@ type MemoryBlockNode struct {
 type MemoryManagementUnit struct {
 	OccupiedRAM  int
 	OccupiedDisk int
-	blockList    *list.List
+	// FailedAllocations - число запросов памяти, для которых не нашлось подходящего пустого сегмента
+	FailedAllocations int
+	// History - показатели фрагментации за последние такты, от старых к новым
+	History   []Fragmentation
+	blockList *list.List
+}
+
+// Fragmentation - показатели внешней фрагментации оперативной памяти на такте
+type Fragmentation struct {
+	Tick int
+	// Holes - число пустых сегментов
+	Holes int
+	// LargestHole - размер наибольшего пустого сегмента
+	LargestHole int
+	// FreeRAM - суммарный размер пустых сегментов
+	FreeRAM int
+	// MeanHole - средний размер пустого сегмента
+	MeanHole float64
+	// External - доля свободной памяти вне наибольшего пустого сегмента: 1 - LargestHole / FreeRAM
+	External float64
+	// FailedAllocations - число неудачных запросов памяти к этому такту
+	FailedAllocations int
+}
+
+// Fragmentation рассчитывает текущие показатели внешней фрагментации
+func (mmu *MemoryManagementUnit) Fragmentation() Fragmentation {
+	f := Fragmentation{Tick: GetProcessTable().tick, FailedAllocations: mmu.FailedAllocations}
+	for e := mmu.blockList.Front(); e != nil; e = e.Next() {
+		val := e.Value.(*MemoryBlockNode)
+		if val.NodeType != MemHole {
+			continue
+		}
+
+		f.Holes++
+		f.FreeRAM += val.Size
+		if val.Size > f.LargestHole {
+			f.LargestHole = val.Size
+		}
+	}
+
+	if f.Holes > 0 {
+		f.MeanHole = float64(f.FreeRAM) / float64(f.Holes)
+	}
+	if f.FreeRAM > 0 {
+		f.External = 1 - float64(f.LargestHole)/float64(f.FreeRAM)
+	}
+
+	return f
+}
+
+// Sample добавляет текущие показатели фрагментации в историю, старые записи отбрасываются
+func (mmu *MemoryManagementUnit) Sample() {
+	mmu.History = append(mmu.History, mmu.Fragmentation())
+	if len(mmu.History) > memoryHistoryLength {
+		mmu.History = mmu.History[1:]
+	}
 }
 
 // Add пытается занести в RAM фрагмент размером size блоков и возвращает указатель на сегмент или nil
+// Пустой сегмент выбирается стратегией размещения из конфигурации
 func (mmu *MemoryManagementUnit) Add(size int) *MemoryBlockNode {
-	// Поиск первого пустого сегмента, который может вместить size блоков
+	e := mmu.findHole(size, GetConfig().Placement)
+	// nil, если не нашли сегмент
+	if e == nil {
+		mmu.FailedAllocations++
+		return nil
+	}
+
+	val := e.Value.(*MemoryBlockNode)
+	// Если размер сегмента равен нужному размеру, просто меняем тип сегмента
+	if val.Size == size {
+		val.NodeType = MemProcess
+		mmu.OccupiedRAM += size
+
+		return val
+	}
+
+	// Если размер сегмента больше размера, делим сегмент на два
+	reserved := &MemoryBlockNode{NodeType: MemProcess, Position: val.Position, Size: size}
+	val.Position = reserved.Position + reserved.Size
+	val.Size -= size
+	mmu.blockList.InsertBefore(reserved, e)
+	mmu.OccupiedRAM += size
+
+	return reserved
+}
+
+// findHole возвращает элемент списка с пустым сегментом, который может вместить size блоков, или nil
+// first-fit выбирает первый такой сегмент, best-fit - наименьший, worst-fit - наибольший
+func (mmu *MemoryManagementUnit) findHole(size int, placement string) *list.Element {
+	var found *list.Element
 	for e := mmu.blockList.Front(); e != nil; e = e.Next() {
 		val := e.Value.(*MemoryBlockNode)
 		// Выбор только пустых сегментов
-		if val.NodeType == MemHole {
-			// Если размер сегмента равен нужному размеру, просто меняем тип сегмента
-			if val.Size == size {
-				val.NodeType = MemProcess
-				mmu.OccupiedRAM += size
-				mmu.OccupiedDisk -= size
-
-				return val
-			}
-			// Если размер сегмента больше размера, делим сегмент на два
-			if val.Size > size {
-				reserved := &MemoryBlockNode{NodeType: MemProcess, Position: val.Position, Size: size}
-				val.Position = reserved.Position + reserved.Size
-				val.Size -= size
-				mmu.blockList.InsertBefore(reserved, e)
-				mmu.OccupiedRAM += size
-				mmu.OccupiedDisk -= size
-
-				return e.Prev().Value.(*MemoryBlockNode)
+		if val.NodeType != MemHole || val.Size < size {
+			continue
+		}
+
+		if found == nil {
+			found = e
+			if placement == FirstFit {
+				return found
 			}
+			continue
+		}
+
+		best := found.Value.(*MemoryBlockNode)
+		if (placement == BestFit && val.Size < best.Size) || (placement == WorstFit && val.Size > best.Size) {
+			found = e
 		}
 	}
 
-	// nil, если не нашли сегмент
-	return nil
+	return found
 }
 
 // Free пытается выгрузить из RAM указанный сегмент, записав или не записав его на диск
@@ -107,6 +214,7 @@ func (mmu *MemoryManagementUnit) Free(block *MemoryBlockNode, isSwap bool) bool
 				if nextVal.NodeType == MemHole {
 					val.Size += nextVal.Size
 					mmu.blockList.Remove(e.Next())
+					logMerge(val)
 				}
 				return true
 			}
@@ -117,6 +225,7 @@ func (mmu *MemoryManagementUnit) Free(block *MemoryBlockNode, isSwap bool) bool
 				if prevVal.NodeType == MemHole {
 					prevVal.Size += val.Size
 					mmu.blockList.Remove(e)
+					logMerge(prevVal)
 				}
 				return true
 			}
@@ -132,17 +241,20 @@ func (mmu *MemoryManagementUnit) Free(block *MemoryBlockNode, isSwap bool) bool
 			case prevVal.NodeType == MemProcess && nextVal.NodeType == MemHole:
 				val.Size += nextVal.Size
 				mmu.blockList.Remove(e.Next())
+				logMerge(val)
 				return true
 			// Если предыдущий сегмент - пустой, а следующий - сегмент процесса => объединить с предыдущим
 			case prevVal.NodeType == MemHole && nextVal.NodeType == MemProcess:
 				prevVal.Size += val.Size
 				mmu.blockList.Remove(e)
+				logMerge(prevVal)
 				return true
 			// Если оба соседних сегмента пустые, объединить три в один сегмент
 			case prevVal.NodeType == MemHole && nextVal.NodeType == MemHole:
 				prevVal.Size += val.Size + nextVal.Size
 				mmu.blockList.Remove(e.Next())
 				mmu.blockList.Remove(e)
+				logMerge(prevVal)
 				return true
 			// Если соседние сегменты - сегменты процесса, ничего не делать
 			case prevVal.NodeType == MemProcess && nextVal.NodeType == MemProcess:
@@ -154,6 +266,16 @@ func (mmu *MemoryManagementUnit) Free(block *MemoryBlockNode, isSwap bool) bool
 	return false
 }
 
+// logMerge записывает в журнал пустой сегмент, получившийся слиянием соседних пустых сегментов
+func logMerge(hole *MemoryBlockNode) {
+	GetEventLog().Log(EventMergedHoles, -1, -1, "начало %d, размер %d", hole.Position, hole.Size)
+}
+
+// ReleaseDisk освобождает на диске место, занятое выгруженным сегментом размером size блоков
+func (mmu *MemoryManagementUnit) ReleaseDisk(size int) {
+	mmu.OccupiedDisk -= size
+}
+
 var memOnce sync.Once
 var mmuInstance *MemoryManagementUnit
 
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"testing"
+)
+
+// newTestMMU создает менеджер памяти с одним пустым сегментом во всю оперативную память
+func newTestMMU() *MemoryManagementUnit {
+	mmu := &MemoryManagementUnit{blockList: list.New()}
+	mmu.blockList.PushFront(&MemoryBlockNode{NodeType: MemHole, Position: 0, Size: MaxRAM})
+
+	return mmu
+}
+
+func TestDiskAccounting(t *testing.T) {
+	tests := []struct {
+		name string
+		// run выполняет операции над памятью процесса размером 100 блоков
+		run  func(mmu *MemoryManagementUnit)
+		disk int
+	}{
+		{"загрузка без выгрузки", func(mmu *MemoryManagementUnit) {
+			mmu.Add(100)
+		}, 0},
+		{"завершение без выгрузки", func(mmu *MemoryManagementUnit) {
+			mmu.Free(mmu.Add(100), false)
+		}, 0},
+		{"выгрузка на диск", func(mmu *MemoryManagementUnit) {
+			mmu.Free(mmu.Add(100), true)
+		}, 100},
+		{"загрузка выгруженного сегмента", func(mmu *MemoryManagementUnit) {
+			mmu.Free(mmu.Add(100), true)
+			mmu.Add(100)
+			mmu.ReleaseDisk(100)
+		}, 0},
+		{"несколько загрузок и выгрузок", func(mmu *MemoryManagementUnit) {
+			for i := 0; i < 3; i++ {
+				mmu.Free(mmu.Add(100), true)
+				mmu.Add(100)
+				mmu.ReleaseDisk(100)
+			}
+			mmu.Free(mmu.Add(50), true)
+		}, 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mmu := newTestMMU()
+			test.run(mmu)
+			if mmu.OccupiedDisk != test.disk {
+				t.Fatalf("OccupiedDisk = %d, ожидалось %d", mmu.OccupiedDisk, test.disk)
+			}
+		})
+	}
+}
+
+func TestDiskNeverNegative(t *testing.T) {
+	startModel(t, 1, nil)
+
+	pt := GetProcessTable()
+	for tick := 0; tick < 2000; tick++ {
+		if tick%3 == 0 {
+			pt.AddProcess()
+		}
+		if tick%50 == 25 && len(pt.table) > 1 {
+			pt.SendSignal(pt.table[1], SigStop)
+		}
+		if tick%50 == 40 && len(pt.table) > 1 {
+			pt.SendSignal(pt.table[1], SigCont)
+		}
+		PerformProcess()
+		ScheduleProcess()
+
+		if disk := GetMMU().OccupiedDisk; disk < 0 {
+			t.Fatalf("такт %d: OccupiedDisk = %d", tick, disk)
+		}
+	}
+}
+
+// fragmentMemory размещает сегменты 100, 300, 100, 200 и 100 блоков и освобождает второй и четвертый,
+// оставляя пустые сегменты 300 блоков с позиции 100, 200 блоков с позиции 500 и остаток памяти с позиции 800
+func fragmentMemory(t *testing.T) {
+	t.Helper()
+
+	mmu := GetMMU()
+	var blocks []*MemoryBlockNode
+	for _, size := range []int{100, 300, 100, 200, 100} {
+		block := mmu.Add(size)
+		if block == nil {
+			t.Fatalf("не удалось разместить сегмент %d блоков", size)
+		}
+		blocks = append(blocks, block)
+	}
+	for _, i := range []int{1, 3} {
+		if !mmu.Free(blocks[i], false) {
+			t.Fatalf("не удалось освободить сегмент %d", i)
+		}
+	}
+}
+
+func TestPlacement(t *testing.T) {
+	tests := []struct {
+		placement string
+		size      int
+		position  int
+	}{
+		{FirstFit, 150, 100},
+		{FirstFit, 250, 100},
+		{FirstFit, 400, 800},
+		{BestFit, 150, 500},
+		{BestFit, 200, 500},
+		{BestFit, 250, 100},
+		{BestFit, 400, 800},
+		{WorstFit, 150, 800},
+		{WorstFit, 300, 800},
+		{FirstFit, MaxRAM, -1},
+		{BestFit, MaxRAM, -1},
+		{WorstFit, MaxRAM, -1},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %d", test.placement, test.size), func(t *testing.T) {
+			startModel(t, 1, func(c *Config) { c.Placement = FirstFit })
+			fragmentMemory(t)
+
+			mmu := GetMMU()
+			GetConfig().Placement = test.placement
+			block := mmu.Add(test.size)
+			switch {
+			case test.position == -1 && block != nil:
+				t.Fatalf("сегмент %d блоков размещен с позиции %d, ожидался отказ", test.size, block.Position)
+			case test.position == -1:
+				if mmu.FailedAllocations != 1 {
+					t.Fatalf("FailedAllocations = %d, ожидалось 1", mmu.FailedAllocations)
+				}
+			case block == nil:
+				t.Fatalf("сегмент %d блоков не размещен, ожидалась позиция %d", test.size, test.position)
+			case block.Position != test.position:
+				t.Fatalf("сегмент %d блоков размещен с позиции %d, ожидалась %d", test.size, block.Position, test.position)
+			}
+		})
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	tests := []struct {
+		placement string
+		valid     bool
+	}{
+		{FirstFit, true},
+		{BestFit, true},
+		{WorstFit, true},
+		{"next-fit", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if err := checkPlacement(test.placement); (err == nil) != test.valid {
+			t.Errorf("checkPlacement(%q) = %v, ожидалась допустимость %v", test.placement, err, test.valid)
+		}
+	}
+}
+
+func TestFragmentation(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T)
+		want    Fragmentation
+	}{
+		{"пустая память", func(t *testing.T) {}, Fragmentation{
+			Holes: 1, LargestHole: MaxRAM, FreeRAM: MaxRAM, MeanHole: MaxRAM,
+		}},
+		{"три пустых сегмента", fragmentMemory, Fragmentation{
+			Holes: 3, LargestHole: MaxRAM - 800, FreeRAM: MaxRAM - 300, MeanHole: float64(MaxRAM-300) / 3,
+			External: 1 - float64(MaxRAM-800)/float64(MaxRAM-300),
+		}},
+		{"память заполнена", func(t *testing.T) {
+			GetMMU().Add(MaxRAM)
+			GetMMU().Add(1)
+		}, Fragmentation{FailedAllocations: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, 1, func(c *Config) { c.Placement = FirstFit })
+			test.prepare(t)
+
+			if got := GetMMU().Fragmentation(); got != test.want {
+				t.Fatalf("Fragmentation() = %+v, ожидалось %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSampleHistory(t *testing.T) {
+	startModel(t, 1, nil)
+
+	pt, mmu := GetProcessTable(), GetMMU()
+	mmu.History = nil
+	for tick := 0; tick < memoryHistoryLength+10; tick++ {
+		pt.tick = tick
+		mmu.Sample()
+	}
+
+	if len(mmu.History) != memoryHistoryLength {
+		t.Fatalf("в истории %d записей, ожидалось %d", len(mmu.History), memoryHistoryLength)
+	}
+	for i, f := range mmu.History {
+		if f.Tick != i+10 {
+			t.Fatalf("запись %d относится к такту %d, ожидался %d", i, f.Tick, i+10)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"operating-systems/processes/uitools"
+)
+
+// maxRandomBatch - наибольшее число случайных процессов, создаваемых за одно нажатие
+const maxRandomBatch = 1000
+
+// defaultSpec возвращает параметры, которыми заполняется окно создания процесса: средние значения
+// случайного процесса и следующее свободное имя
+// Генератор случайных чисел не используется, чтобы открытие окна не меняло прогон записанного сеанса
+func defaultSpec() ProcessSpec {
+	return ProcessSpec{Name: fmt.Sprintf("proc%d", GetProcessTable().processCounter),
+		Memory:    MaxRAM / 512,
+		Cycles:    GetConfig().MaxThreads + 512,
+		Priority:  0,
+		IOProfile: CPUBound}
+}
+
+// newCreateDialog создает окно ввода параметров нового процесса по центру экрана
+// Поля заполняются параметрами по умолчанию, done получает сообщение о результате
+// Процессы создаются действиями сеанса, чтобы попасть в запись
+func newCreateDialog(done func(message string)) *uitools.Dialog {
+	theme := uitools.CurrentTheme
+	spec := defaultSpec()
+
+	width, height := 60, 22
+	if width > screenWidth {
+		width = screenWidth
+	}
+	x, y := (screenWidth-width)/2, 1+(screenHeight-1-height)/2
+
+	var dialog *uitools.Dialog
+	message := uitools.NewLabel(0, 0, fmt.Sprintf("Память до %d, приоритет до %d, потребность до %v", MaxRAM, MaxPriority, GetConfig().Resources), theme.Normal.Fg, theme.Normal.Bg)
+
+	nameInput := uitools.NewTextInput(0, 0, 24, spec.Name, theme.Normal.Fg, theme.Normal.Bg, nil)
+	memoryInput := uitools.NewTextInput(0, 0, 24, fmt.Sprint(spec.Memory), theme.Normal.Fg, theme.Normal.Bg, nil)
+	cyclesInput := uitools.NewTextInput(0, 0, 24, fmt.Sprint(spec.Cycles), theme.Normal.Fg, theme.Normal.Bg, nil)
+	priorityInput := uitools.NewTextInput(0, 0, 24, fmt.Sprint(spec.Priority), theme.Normal.Fg, theme.Normal.Bg, nil)
+	claimInput := uitools.NewTextInput(0, 0, 24, "", theme.Normal.Fg, theme.Normal.Bg, nil)
+	affinityInput := uitools.NewTextInput(0, 0, 24, "", theme.Normal.Fg, theme.Normal.Bg, nil)
+
+	profiles := make([]string, len(ioProfiles))
+	for i, io := range ioProfiles {
+		profiles[i] = io.Stringify()
+	}
+	profileList := uitools.NewListBox(0, 0, 24, len(profiles), profiles, theme.Normal.Fg, theme.Normal.Bg, nil)
+	profileList.Select(int(spec.IOProfile))
+
+	countInput := uitools.NewTextInput(0, 0, 6, "10", theme.Normal.Fg, theme.Normal.Bg, nil)
+
+	// Числовое поле разбирается с указанием его названия в сообщении об ошибке
+	number := func(input *uitools.TextInput, caption string) (int, error) {
+		value, err := strconv.Atoi(strings.TrimSpace(input.Text()))
+		if err != nil {
+			return 0, fmt.Errorf("%s: ожидается целое число", caption)
+		}
+		return value, nil
+	}
+
+	// Потребность в ресурсах задается через запятую, пустое поле - случайная потребность
+	claim := func(input *uitools.TextInput) ([]int, error) {
+		text := strings.TrimSpace(input.Text())
+		if text == "" {
+			return nil, nil
+		}
+		values, err := parseIntList(text, "Потребность")
+		if err != nil {
+			return nil, errors.New("Потребность: ожидаются целые числа через запятую")
+		}
+		return values, nil
+	}
+
+	// Привязка задается маской процессоров, пустое поле - все процессоры
+	affinity := func(input *uitools.TextInput) (uint64, error) {
+		text := strings.TrimSpace(input.Text())
+		if text == "" {
+			return 0, nil
+		}
+		return parseMask(text, "Привязка")
+	}
+
+	// Поля окна разбираются по порядку до первой ошибки
+	parseSpec := func() (spec ProcessSpec, err error) {
+		spec = ProcessSpec{Name: strings.TrimSpace(nameInput.Text()), IOProfile: ioProfiles[profileList.Selected()]}
+		if spec.Memory, err = number(memoryInput, "Память"); err != nil {
+			return
+		}
+		if spec.Cycles, err = number(cyclesInput, "Такты"); err != nil {
+			return
+		}
+		if spec.Priority, err = number(priorityInput, "Приоритет"); err != nil {
+			return
+		}
+		if spec.MaxClaim, err = claim(claimInput); err != nil {
+			return
+		}
+		spec.Affinity, err = affinity(affinityInput)
+		return
+	}
+
+	create := func(*uitools.Button) {
+		spec, err := parseSpec()
+		if err == nil {
+			err = GetSession().Do(Action{Kind: ActionCreate, Spec: &spec})
+		}
+		if err != nil {
+			message.SetText(err.Error())
+			return
+		}
+
+		done(fmt.Sprintf("Создан процесс %s", spec.Name))
+		dialog.Close()
+	}
+
+	createRandom := func(*uitools.Button) {
+		count, err := number(countInput, "Количество")
+		if err == nil && (count < 1 || count > maxRandomBatch) {
+			err = fmt.Errorf("Количество: от 1 до %d", maxRandomBatch)
+		}
+		if err != nil {
+			message.SetText(err.Error())
+			return
+		}
+
+		if err := GetSession().Do(Action{Kind: ActionCreateRandom, Value: count}); err != nil {
+			message.SetText(err.Error())
+			return
+		}
+		done(fmt.Sprintf("Создано случайных процессов: %d", count))
+		dialog.Close()
+	}
+
+	createButton := uitools.NewButton(0, 0, "Создать", theme.Normal.Fg, theme.Normal.Bg, create)
+	randomButton := uitools.NewButton(0, 0, "Создать случайные", theme.Normal.Fg, theme.Normal.Bg, createRandom)
+
+	grid := uitools.NewGridLayout(2, 1).
+		Add(uitools.NewLabel(0, 0, "Имя", theme.Normal.Fg, theme.Normal.Bg)).Add(nameInput).
+		Add(uitools.NewLabel(0, 0, "Память", theme.Normal.Fg, theme.Normal.Bg)).Add(memoryInput).
+		Add(uitools.NewLabel(0, 0, "Такты", theme.Normal.Fg, theme.Normal.Bg)).Add(cyclesInput).
+		Add(uitools.NewLabel(0, 0, "Приоритет", theme.Normal.Fg, theme.Normal.Bg)).Add(priorityInput).
+		Add(uitools.NewLabel(0, 0, "Потребность", theme.Normal.Fg, theme.Normal.Bg)).Add(claimInput).
+		Add(uitools.NewLabel(0, 0, "Привязка (0b...)", theme.Normal.Fg, theme.Normal.Bg)).Add(affinityInput).
+		Add(uitools.NewLabel(0, 0, "Ввод-вывод", theme.Normal.Fg, theme.Normal.Bg)).Add(profileList)
+	batch := uitools.NewHorizontalLayout(2).
+		Add(createButton).
+		Add(uitools.NewLabel(0, 0, "Количество", theme.Normal.Fg, theme.Normal.Bg)).
+		Add(countInput).
+		Add(randomButton)
+
+	focus := uitools.NewFocusGroup(nameInput, memoryInput, cyclesInput, priorityInput, claimInput, affinityInput, profileList, createButton, countInput, randomButton)
+	layout := uitools.NewVerticalLayout(1).Add(grid).Add(batch).Add(message).WithFocus(focus)
+	layout.Arrange(x+2, y+2, width-4, height-3)
+
+	dialog = uitools.NewDialog(x, y, width, height, "Новый процесс", theme.Normal.Fg, theme.Normal.Bg, layout)
+	dialog.Open()
+
+	return dialog
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"operating-systems/processes/uitools"
+)
+
+// processFilter - условия отбора процессов для таблицы, пустые условия не ограничивают отбор
+type processFilter struct {
+	// State - начало названия состояния без учета регистра
+	State string
+	// Name - подстрока имени процесса
+	Name string
+	// MinMemory и MaxMemory - границы объема памяти, -1 если граница не задана
+	MinMemory, MaxMemory int
+}
+
+// newProcessFilter создает фильтр, пропускающий все процессы
+func newProcessFilter() processFilter {
+	return processFilter{MinMemory: -1, MaxMemory: -1}
+}
+
+// IsEmpty сообщает, что фильтр не задает ни одного условия
+func (f processFilter) IsEmpty() bool {
+	return f.State == "" && f.Name == "" && f.MinMemory == -1 && f.MaxMemory == -1
+}
+
+// Matches проверяет, удовлетворяет ли процесс всем условиям фильтра
+func (f processFilter) Matches(p *Process) bool {
+	if f.State != "" && !strings.HasPrefix(strings.ToLower(p.State.Stringify()), strings.ToLower(f.State)) {
+		return false
+	}
+	if f.Name != "" && !strings.Contains(p.Name, f.Name) {
+		return false
+	}
+	if f.MinMemory != -1 && p.Memory < f.MinMemory {
+		return false
+	}
+	if f.MaxMemory != -1 && p.Memory > f.MaxMemory {
+		return false
+	}
+
+	return true
+}
+
+// String описывает условия фильтра для строки над таблицей
+func (f processFilter) String() string {
+	var parts []string
+	if f.State != "" {
+		parts = append(parts, "состояние "+f.State)
+	}
+	if f.Name != "" {
+		parts = append(parts, "имя содержит \""+f.Name+"\"")
+	}
+	if f.MinMemory != -1 || f.MaxMemory != -1 {
+		bound := func(v int) string {
+			if v == -1 {
+				return "*"
+			}
+			return fmt.Sprint(v)
+		}
+		parts = append(parts, "память "+bound(f.MinMemory)+".."+bound(f.MaxMemory))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// processLess сравнивает процессы по значению столбца таблицы процессов
+func processLess(a, b *Process, column int) bool {
+	switch column {
+	case 0:
+		return a.PID < b.PID
+	case 1:
+		return a.Name < b.Name
+	case 2:
+		return a.Memory < b.Memory
+	case 3:
+		return a.State < b.State
+	case 4:
+		return a.CPUTime < b.CPUTime
+	case 5:
+		return a.CyclesRemains < b.CyclesRemains
+	case 6:
+		return a.TimeSlot < b.TimeSlot
+	case 7:
+		return a.CPU < b.CPU
+	}
+
+	return false
+}
+
+// filterProcesses отбирает процессы фильтром и упорядочивает их по столбцу, при column == -1 порядок таблицы сохраняется
+// Сортировка устойчива, поэтому процессы с равными значениями остаются в порядке таблицы
+func filterProcesses(processes []*Process, filter processFilter, column int, descending bool) []*Process {
+	var view []*Process
+	for _, p := range processes {
+		if filter.Matches(p) {
+			view = append(view, p)
+		}
+	}
+
+	if column != -1 {
+		sort.SliceStable(view, func(i, j int) bool {
+			if descending {
+				return processLess(view[j], view[i], column)
+			}
+			return processLess(view[i], view[j], column)
+		})
+	}
+
+	return view
+}
+
+// parseBound переводит границу диапазона памяти в число, пустая строка означает отсутствие границы
+func parseBound(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return -1, nil
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil || value < 0 {
+		return -1, fmt.Errorf("некорректная граница памяти: %q", text)
+	}
+
+	return value, nil
+}
+
+// newFilterDialog создает окно ввода условий фильтра по центру экрана
+// Enter в любом поле применяет фильтр и закрывает окно, Escape закрывает окно без изменений
+func newFilterDialog(filter processFilter, apply func(processFilter)) *uitools.Dialog {
+	theme := uitools.CurrentTheme
+
+	width, height := 48, 11
+	if width > screenWidth {
+		width = screenWidth
+	}
+	x, y := (screenWidth-width)/2, 1+(screenHeight-1-height)/2
+
+	bound := func(v int) string {
+		if v == -1 {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+
+	message := uitools.NewLabel(0, 0, "Пустое поле не ограничивает отбор", theme.Normal.Fg, theme.Normal.Bg)
+	var dialog *uitools.Dialog
+	var inputs []*uitools.TextInput
+
+	submit := func(*uitools.TextInput) {
+		minMemory, err := parseBound(inputs[2].Text())
+		if err != nil {
+			message.SetText(err.Error())
+			return
+		}
+		maxMemory, err := parseBound(inputs[3].Text())
+		if err != nil {
+			message.SetText(err.Error())
+			return
+		}
+		if minMemory != -1 && maxMemory != -1 && minMemory > maxMemory {
+			message.SetText(fmt.Sprintf("нижняя граница памяти %d больше верхней %d", minMemory, maxMemory))
+			return
+		}
+
+		filter.State = strings.TrimSpace(inputs[0].Text())
+		filter.Name = strings.TrimSpace(inputs[1].Text())
+		filter.MinMemory, filter.MaxMemory = minMemory, maxMemory
+		apply(filter)
+		dialog.Close()
+	}
+
+	fields := []struct {
+		caption string
+		value   string
+	}{
+		{"Состояние", filter.State},
+		{"Имя содержит", filter.Name},
+		{"Память от", bound(filter.MinMemory)},
+		{"Память до", bound(filter.MaxMemory)},
+	}
+
+	grid := uitools.NewGridLayout(2, 1)
+	focusable := []uitools.Widget{}
+	for _, f := range fields {
+		input := uitools.NewTextInput(0, 0, 20, f.value, theme.Normal.Fg, theme.Normal.Bg, submit)
+		inputs = append(inputs, input)
+		focusable = append(focusable, input)
+		grid.Add(uitools.NewLabel(0, 0, f.caption, theme.Normal.Fg, theme.Normal.Bg)).Add(input)
+	}
+
+	focus := uitools.NewFocusGroup(focusable...)
+	layout := uitools.NewVerticalLayout(1).Add(grid).Add(message).WithFocus(focus)
+	layout.Arrange(x+2, y+2, width-4, height-3)
+
+	dialog = uitools.NewDialog(x, y, width, height, "Фильтр процессов", theme.Normal.Fg, theme.Normal.Bg, layout)
+	dialog.Open()
+
+	return dialog
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// processInspector - панель с полным управляющим блоком процесса, обновляемая на каждом кадре
+// Хранится PID, а не процесс: перемотка и загрузка снимка заменяют объекты процессов
+type processInspector struct {
+	x, y, width, height int
+	pid                 int
+}
+
+// lookup возвращает процесс панели из таблицы процессов или из завершенных, nil если его нет в модели
+func (pi *processInspector) lookup() *Process {
+	pt := GetProcessTable()
+	if p := pt.find(pi.pid); p != nil {
+		return p
+	}
+	for _, p := range pt.finished {
+		if p.PID == pi.pid {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Draw отображает сведения о процессе, строки обрезаются по ширине панели
+func (pi *processInspector) Draw() {
+	theme := uitools.CurrentTheme
+
+	process := pi.lookup()
+	if process == nil {
+		uitools.Print(pi.x, pi.y, theme.Normal.Fg, theme.Normal.Bg, fmt.Sprintf("Процесса %d нет в модели", pi.pid))
+		return
+	}
+
+	for i, line := range process.Details() {
+		if i >= pi.height {
+			break
+		}
+
+		runes := []rune(line)
+		if len(runes) > pi.width {
+			runes = runes[:pi.width]
+		}
+		uitools.Print(pi.x, pi.y+i, theme.Normal.Fg, theme.Normal.Bg, string(runes))
+	}
+}
+
+// HandleEvent не обрабатывает события: панель только отображает состояние
+func (pi *processInspector) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// newInspectorDialog создает окно с панелью процесса по центру экрана
+func newInspectorDialog(process *Process) *uitools.Dialog {
+	theme := uitools.CurrentTheme
+
+	width, height := 72, 22
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight-1 {
+		height = screenHeight - 1
+	}
+	x, y := (screenWidth-width)/2, 1+(screenHeight-1-height)/2
+
+	inspector := &processInspector{x + 2, y + 1, width - 4, height - 2, process.PID}
+	dialog := uitools.NewDialog(x, y, width, height, fmt.Sprintf("Процесс %s (PID %d)", process.Name, process.PID),
+		theme.Normal.Fg, theme.Normal.Bg, inspector)
+	dialog.Open()
+
+	return dialog
+}
+
+// Details формирует строки с содержимым управляющего блока процесса
+func (p *Process) Details() []string {
+	pt := GetProcessTable()
+
+	state := p.State.Stringify()
+	if !pt.contains(p) {
+		state = Terminated.Stringify() + " (удален из таблицы)"
+	}
+
+	parent := "-"
+	for _, v := range pt.table {
+		if p.PPID != -1 && v.PID == p.PPID {
+			parent = fmt.Sprintf("%s (PID %d)", v.Name, v.PID)
+		}
+	}
+
+	memory := "не выделен"
+	if p.MemoryBlock != nil {
+		memory = fmt.Sprintf("начало %d, размер %d", p.MemoryBlock.Position, p.MemoryBlock.Size)
+	} else if p.Swapped {
+		memory = "выгружен на диск"
+	}
+
+	cpu, lastCPU := "-", "-"
+	if p.CPU != -1 {
+		cpu = fmt.Sprint(p.CPU)
+	}
+	if p.LastCPU != -1 {
+		lastCPU = fmt.Sprint(p.LastCPU)
+	}
+
+	deferred := ""
+	if p.Deferred {
+		deferred = " (отложен)"
+	}
+
+	signals := make([]string, len(p.PendingSignals))
+	for i, s := range p.PendingSignals {
+		signals[i] = s.Stringify()
+	}
+	if len(signals) == 0 {
+		signals = append(signals, "нет")
+	}
+
+	history := make([]string, len(p.StateHistory))
+	for i, h := range p.StateHistory {
+		history[i] = fmt.Sprintf("%d:%s", h.Tick, h.State.Stringify())
+	}
+
+	quanta := make([]string, len(p.QuantumHistory))
+	for i, q := range p.QuantumHistory {
+		quanta[i] = fmt.Sprint(q)
+	}
+	if len(quanta) == 0 {
+		quanta = append(quanta, "-")
+	}
+
+	lines := []string{
+		fmt.Sprintf("PID: %d  GID: %d  Родитель: %s", p.PID, p.GID, parent),
+		fmt.Sprintf("Состояние: %s  Приоритет: %d  Профиль: %s", state, p.Priority, p.IOProfile.Stringify()),
+		fmt.Sprintf("Время CPU: %d  Осталось тактов: %d  Квант: %d", p.CPUTime, p.CyclesRemains, p.TimeSlot),
+		fmt.Sprintf("Процессор: %s  Последний: %s  Привязка: %b", cpu, lastCPU, p.Affinity),
+		fmt.Sprintf("Миграций: %d  Переключений контекста: %d  Потоков: %d", p.Migrations, p.ContextSwitches, len(p.Threads)),
+		fmt.Sprintf("Память: %d  Сегмент: %s", p.Memory, memory),
+		"",
+		fmt.Sprintf("Max: %s  Allocation: %s", formatVector(p.MaxClaim), formatVector(p.Allocation)),
+		fmt.Sprintf("Need: %s  Запрос: %s%s", formatVector(needOf(p)), formatVector(p.Request), deferred),
+		fmt.Sprintf("Ожидающие сигналы: %s", strings.Join(signals, ", ")),
+		"",
+		"История квантов: " + strings.Join(quanta, " "),
+		"История состояний (такт:состояние):",
+	}
+
+	// История состояний выводится по несколько записей в строке, последние записи внизу
+	for i := 0; i < len(history); i += 4 {
+		end := i + 4
+		if end > len(history) {
+			end = len(history)
+		}
+		lines = append(lines, "  "+strings.Join(history[i:end], "  "))
+	}
+
+	return lines
+}
+
+// needOf возвращает остаточную потребность процесса или nil, если процесс не заявлял ресурсов
+func needOf(p *Process) []int {
+	if p.MaxClaim == nil {
+		return nil
+	}
+
+	return p.Need()
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// processScreen - экран диспетчера задач: таблица процессов, кнопки управления и потоки выделенного процесса
+type processScreen struct {
+	// view - процессы в порядке строк таблицы после фильтрации и сортировки
+	view        []*Process
+	filter      processFilter
+	filterLabel *uitools.Label
+	processView *uitools.Table
+	threadLabel *uitools.Label
+	threadView  *uitools.Table
+	buttons     *uitools.Layout
+	layout      *uitools.Layout
+	events      *uitools.EventNode
+	// message - результат последнего действия для строки состояния
+	message string
+	// modal - открытое окно процесса или фильтра, nil пока ни одно не открывалось
+	modal *uitools.Dialog
+}
+
+// newProcessScreen создает экран диспетчера задач
+func newProcessScreen() *processScreen {
+	theme := uitools.CurrentTheme
+	s := &processScreen{filter: newProcessFilter()}
+
+	s.processView = uitools.NewTable(0, 0, processTableColumns, 11, theme.Normal.Fg, theme.Normal.Bg, nil)
+	s.processView.SetRowStyle(func(i int) uitools.Style {
+		if i < len(s.view) {
+			return stateStyle(s.view[i].State)
+		}
+		return theme.Normal
+	})
+	s.processView.SetSortAction(func(t *uitools.Table) {
+		s.refresh()
+	})
+	s.filterLabel = uitools.NewLabel(0, 0, "", theme.Normal.Fg, theme.Normal.Bg)
+	s.threadLabel = uitools.NewLabel(0, 0, "", theme.Normal.Fg, theme.Normal.Bg)
+	s.threadView = uitools.NewTable(0, 0, threadTableColumns, GetConfig().MaxThreads, theme.Normal.Fg, theme.Normal.Bg, nil)
+	s.threadView.SetRowStyle(func(i int) uitools.Style {
+		if p := s.selectedProcess(); p != nil && i < len(p.Threads) {
+			return stateStyle(p.Threads[i].State)
+		}
+		return theme.Normal
+	})
+
+	createProcessButton := uitools.NewButton(0, 0, "Создать процесс", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.modal = newCreateDialog(func(message string) {
+				s.message = message
+			})
+		})
+
+	// Действия над процессами выполняются через сеанс, чтобы попасть в запись
+	blockProcessButton := uitools.NewButton(0, 0, "Блокировать процесс    ", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.do(ActionBlock, 0, "Процесс %d заблокирован")
+		})
+
+	unblockProcessButton := uitools.NewButton(0, 0, "Разблокировать процесс    ", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.do(ActionUnblock, 0, "Процесс %d разблокирован")
+		})
+
+	// Завершение, приостановка и возобновление передаются диспетчеру сигналами и выполняются в начале такта
+	killProcessButton := uitools.NewButton(0, 0, "Завершить", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.signal(SigKill)
+		})
+	suspendProcessButton := uitools.NewButton(0, 0, "Приостановить", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.signal(SigStop)
+		})
+	resumeProcessButton := uitools.NewButton(0, 0, "Возобновить", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.signal(SigCont)
+		})
+	raisePriorityButton := uitools.NewButton(0, 0, "Приоритет +", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.changePriority(1)
+		})
+	lowerPriorityButton := uitools.NewButton(0, 0, "Приоритет -", theme.Normal.Fg, theme.Normal.Bg,
+		func(b *uitools.Button) {
+			s.changePriority(-1)
+		})
+
+	// Порядок важен: кнопки используют выделение, которое таблица снимает при нажатии вне ее
+	// Фокус клавиатуры изначально у таблицы процессов, чтобы стрелки сразу выбирали строки
+	focus := uitools.NewFocusGroup(createProcessButton, blockProcessButton, unblockProcessButton,
+		killProcessButton, suspendProcessButton, resumeProcessButton, raisePriorityButton, lowerPriorityButton, s.processView)
+	focus.Focus(s.processView)
+
+	// Таблица процессов занимает все место между кнопками и таблицей потоков,
+	// первая строка экрана занята меню, последняя отводится под строку состояния
+	s.buttons = uitools.NewVerticalLayout(0).
+		Add(uitools.NewHorizontalLayout(2).Add(createProcessButton).Add(blockProcessButton).Add(unblockProcessButton)).
+		Add(uitools.NewHorizontalLayout(2).Add(killProcessButton).Add(suspendProcessButton).Add(resumeProcessButton).
+			Add(raisePriorityButton).Add(lowerPriorityButton))
+	s.layout = uitools.NewVerticalLayout(0).
+		Add(s.buttons).
+		Add(s.filterLabel).
+		AddStretch(s.processView).
+		Add(s.threadLabel).
+		Add(s.threadView)
+
+	// Открытое окно получает события раньше остальных элементов экрана
+	s.events = uitools.NewEventNode()
+	s.events.Child().
+		On(uitools.MouseEvent, s.handleModal).
+		On(uitools.KeyEvent, s.handleModal)
+	s.events.AddWidget(focus)
+	s.events.On(uitools.KeyEvent, s.toggleAffinity)
+	s.events.On(uitools.KeyEvent, s.openInspector)
+	s.events.On(uitools.KeyEvent, s.openFilter)
+	s.events.On(uitools.ResizeEvent, func(ev *termbox.Event) bool {
+		s.layout.Arrange(0, 1, ev.Width, ev.Height-2)
+		return false
+	})
+
+	s.layout.Arrange(0, 1, screenWidth, screenHeight-2)
+	s.refresh()
+	return s
+}
+
+// refresh пересчитывает строки таблицы по фильтру и сортировке, сохраняя выбор процесса
+// Выбор сохраняется по PID: перемотка и загрузка снимка заменяют объекты процессов
+func (s *processScreen) refresh() {
+	selected := s.selectedPID()
+	column, descending := s.processView.Sort()
+	s.view = filterProcesses(GetProcessTable().table, s.filter, column, descending)
+
+	s.processView.SetRows(processRows(s.view))
+	s.processView.SetSelected(s.rowOf(selected))
+
+	if s.filter.IsEmpty() {
+		s.filterLabel.SetText("Фильтр: нет (/ - задать)")
+	} else {
+		s.filterLabel.SetText(fmt.Sprintf("Фильтр: %s (показано %d из %d)", s.filter, len(s.view), len(GetProcessTable().table)))
+	}
+}
+
+// rowOf возвращает индекс строки процесса с заданным PID в таблице или -1, если процесс не показан
+func (s *processScreen) rowOf(pid int) int {
+	for i, p := range s.view {
+		if p.PID == pid {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// selectedPID возвращает PID выделенного в таблице процесса или -1
+func (s *processScreen) selectedPID() int {
+	if selected := s.processView.Selected(); selected != -1 && selected < len(s.view) {
+		return s.view[selected].PID
+	}
+
+	return -1
+}
+
+// selectedProcess возвращает выделенный процесс из текущей таблицы процессов или nil
+func (s *processScreen) selectedProcess() *Process {
+	if pid := s.selectedPID(); pid != -1 {
+		return GetProcessTable().find(pid)
+	}
+
+	return nil
+}
+
+// toggleAffinity переключает привязку выделенного процесса между всеми процессорами и последним использованным
+func (s *processScreen) toggleAffinity(ev *termbox.Event) bool {
+	if ev.Ch != 'a' || s.selectedProcess() == nil {
+		return false
+	}
+
+	s.do(ActionAffinity, 0, "Привязка процесса %d изменена")
+	return true
+}
+
+// do выполняет действие над выделенным процессом и сообщает результат в строке состояния
+// Сообщение об успехе получает PID процесса
+func (s *processScreen) do(kind ActionKind, value int, format string) {
+	p := s.selectedProcess()
+	if p == nil {
+		return
+	}
+
+	if err := GetSession().Do(Action{Kind: kind, PID: p.PID, Value: value}); err != nil {
+		s.message = err.Error()
+		return
+	}
+	s.message = fmt.Sprintf(format, p.PID)
+}
+
+// signal отправляет сигнал выделенному процессу
+func (s *processScreen) signal(signal Signal) {
+	s.do(ActionSignal, int(signal), signal.Stringify()+" отправлен процессу %d")
+}
+
+// changePriority меняет приоритет выделенного процесса на delta
+func (s *processScreen) changePriority(delta int) {
+	if p := s.selectedProcess(); p != nil {
+		s.do(ActionPriority, p.Priority+delta, "Приоритет процесса %d изменен")
+	}
+}
+
+// openInspector открывает окно выделенного процесса по Enter или 'i'
+func (s *processScreen) openInspector(ev *termbox.Event) bool {
+	p := s.selectedProcess()
+	if (ev.Key != termbox.KeyEnter && ev.Ch != 'i') || p == nil {
+		return false
+	}
+
+	s.modal = newInspectorDialog(p)
+	return true
+}
+
+// openFilter открывает окно фильтра по '/'
+func (s *processScreen) openFilter(ev *termbox.Event) bool {
+	if ev.Ch != '/' {
+		return false
+	}
+
+	s.modal = newFilterDialog(s.filter, func(filter processFilter) {
+		s.filter = filter
+		s.refresh()
+	})
+	return true
+}
+
+// handleModal передает событие открытому окну
+func (s *processScreen) handleModal(ev *termbox.Event) bool {
+	return s.modal != nil && s.modal.HandleEvent(ev)
+}
+
+// Title возвращает название экрана
+func (s *processScreen) Title() string {
+	return "Процессы"
+}
+
+// Enter вызывается при переходе на экран
+func (s *processScreen) Enter() {}
+
+// Leave закрывает открытое окно
+func (s *processScreen) Leave() {
+	s.modal = nil
+}
+
+// HandleEvent передает событие элементам экрана
+func (s *processScreen) HandleEvent(ev *termbox.Event) bool {
+	return s.events.Dispatch(ev)
+}
+
+// Draw отображает таблицу процессов, указатели Round-Robin и потоки выделенного процесса
+func (s *processScreen) Draw() {
+	theme := uitools.CurrentTheme
+	processTable := GetProcessTable()
+
+	s.refresh()
+	s.buttons.Draw()
+	s.filterLabel.Draw()
+	s.processView.Draw()
+
+	// Указатели Round-Robin процессоров отмечаются номером процессора
+	for _, cpu := range processTable.cpus {
+		if y := s.processView.RowY(s.rowOf(processTable.table[cpu.roundRobinProcessIndex%len(processTable.table)].PID)); y != -1 {
+			termbox.SetCell(s.processView.Width()+cpu.ID, y, rune('0'+cpu.ID%10), theme.Status.Fg, theme.Status.Bg)
+		}
+	}
+
+	drawStatusBar()
+	drawCPUStatus()
+	if s.message != "" {
+		uitools.Print(statusBarX+1+len(processTable.cpus)*13, statusBarY, theme.Status.Fg, theme.Status.Bg, s.message)
+	}
+
+	// Таблица потоков выделенного процесса
+	if p := s.selectedProcess(); p != nil {
+		s.threadLabel.SetText(fmt.Sprintf("Потоки процесса %s (PID %d)", p.Name, p.PID))
+		s.threadLabel.Draw()
+		s.threadView.SetRows(p.ThreadRows())
+		s.threadView.Draw()
+	}
+
+	if s.modal != nil {
+		s.modal.Draw()
+	}
+}
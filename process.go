@@ -10,6 +10,10 @@ const (
 	Readiness
 	// Blocking о блокировке прерыванием
 	Blocking
+	// Terminated о завершении потока
+	Terminated
+	// Suspended о приостановке процесса с выгрузкой его памяти на диск
+	Suspended
 )
 
 // Stringify переводит вариант перечисления в строку
@@ -21,20 +25,169 @@ func (ps ProcessState) Stringify() string {
 		return "Готовность"
 	case Blocking:
 		return "Блокировка"
+	case Terminated:
+		return "Завершен"
+	case Suspended:
+		return "Приостановлен"
 	}
 
 	return ""
 }
 
+// Signal перечисляет сигналы, которые могут ожидать доставки процессу
+type Signal int
+
+const (
+	// SigKill требует аварийного завершения процесса
+	SigKill Signal = iota
+	// SigStop требует приостановки процесса
+	SigStop
+	// SigCont требует возобновления приостановленного процесса
+	SigCont
+)
+
+// Stringify переводит вариант перечисления в строку
+func (s Signal) Stringify() string {
+	switch s {
+	case SigKill:
+		return "SIGKILL"
+	case SigStop:
+		return "SIGSTOP"
+	case SigCont:
+		return "SIGCONT"
+	}
+
+	return ""
+}
+
+// IOProfile описывает, насколько часто процесс блокируется в ожидании ввода-вывода
+type IOProfile int
+
+const (
+	// CPUBound - процесс только вычисляет и не блокируется сам
+	CPUBound IOProfile = iota
+	// Balanced - процесс иногда ожидает ввода-вывода
+	Balanced
+	// IOBound - процесс часто ожидает ввода-вывода
+	IOBound
+)
+
+// ioProfiles - все профили ввода-вывода в порядке перечисления
+var ioProfiles = []IOProfile{CPUBound, Balanced, IOBound}
+
+// Stringify переводит вариант перечисления в строку
+func (io IOProfile) Stringify() string {
+	switch io {
+	case CPUBound:
+		return "Вычисления"
+	case Balanced:
+		return "Смешанный"
+	case IOBound:
+		return "Ввод-вывод"
+	}
+
+	return ""
+}
+
+// blockProbability возвращает вероятность блокировки на вводе-выводе после исполненного кванта
+func (io IOProfile) blockProbability() float64 {
+	switch io {
+	case Balanced:
+		return 0.2
+	case IOBound:
+		return 0.5
+	}
+
+	return 0
+}
+
+// MaxPriority - наибольший приоритет процесса
+const MaxPriority = 4
+
+// historyLength - число последних записей, хранимых в истории состояний и квантов процесса
+const historyLength = 16
+
+// StateChange - запись истории состояний: такт, на котором процесс перешел в состояние
+type StateChange struct {
+	Tick  int
+	State ProcessState
+}
+
 // Process представляет процесс вместе с управляющим блоком
 type Process struct {
-	Name          string
-	Memory        int
-	MemoryBlock   *MemoryBlockNode
+	Name   string
+	Memory int
+	// MemoryBlock - сегмент процесса в списке блоков памяти, в снимке заменяется номером сегмента
+	MemoryBlock *MemoryBlockNode `json:"-"`
+	// Swapped - сегмент процесса выгружен на диск
+	Swapped       bool
 	CyclesRemains int
 	TimeSlot      int
 	State         ProcessState
 	PID           int
 	CPUTime       int
 	GID           int
+	// PPID - идентификатор родительского процесса, -1 у init
+	PPID int
+	// Priority - приоритет от 0 до MaxPriority, начальный квант процесса и его потоков равен 2^Priority
+	Priority int
+	// IOProfile - частота блокировок процесса на вводе-выводе
+	IOProfile IOProfile
+	// IOWaitRemains - оставшиеся такты ожидания ввода-вывода, 0 если процесс не ждет завершения операции
+	IOWaitRemains int
+	// CPU - процессор, в очереди которого стоит процесс при раздельных очередях, -1 если очереди общие
+	CPU int
+	// Affinity - маска процессоров, на которых разрешено исполнение процесса
+	Affinity uint64
+	// LastCPU - процессор, на котором процесс исполнялся последним, -1 если не исполнялся
+	LastCPU int
+	// Migrations - число переходов процесса между процессорами
+	Migrations int
+	// ContextSwitches - число переключений контекста на этот процесс
+	ContextSwitches int
+	// Threads - потоки процесса, разделяющие его сегмент памяти
+	Threads     []*Thread
+	threadIndex int
+	// MaxClaim - заявленная максимальная потребность в ресурсах каждого типа
+	MaxClaim []int
+	// Allocation - выделенные процессу ресурсы
+	Allocation []int
+	// Request - текущий невыполненный запрос ресурсов
+	Request []int
+	// Deferred - запрос процесса отложен распределителем ресурсов
+	Deferred bool
+	// StateHistory - последние смены состояния процесса
+	StateHistory []StateChange
+	// QuantumHistory - последние кванты времени, выделенные процессу или его потокам
+	QuantumHistory []int
+	// PendingSignals - сигналы, ожидающие доставки процессу
+	PendingSignals []Signal
+	// Arrival и Completion - такты создания и завершения процесса, Completion равен -1 до завершения
+	Arrival    int
+	Completion int
+	// WaitTicks - число тактов, проведенных процессом в очереди готовых
+	WaitTicks int
+	// Swaps - число выгрузок сегмента процесса на диск
+	Swaps int
+}
+
+// SetState переводит процесс в состояние и записывает смену состояния в историю
+func (p *Process) SetState(state ProcessState) {
+	if p.State == state && len(p.StateHistory) != 0 {
+		return
+	}
+
+	p.State = state
+	p.StateHistory = append(p.StateHistory, StateChange{GetProcessTable().tick, state})
+	if len(p.StateHistory) > historyLength {
+		p.StateHistory = p.StateHistory[1:]
+	}
+}
+
+// recordQuantum записывает выделенный квант времени в историю квантов
+func (p *Process) recordQuantum(quantum int) {
+	p.QuantumHistory = append(p.QuantumHistory, quantum)
+	if len(p.QuantumHistory) > historyLength {
+		p.QuantumHistory = p.QuantumHistory[1:]
+	}
 }
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Session - сеанс работы модели: начальное значение генератора случайных чисел, конфигурация
+// и действия пользователя с тактами, на которых они выполнены
+// По записанному сеансу прогон модели воспроизводится в точности, поэтому к любому пройденному такту
+// можно вернуться: от сохраненного состояния модели повторяются такты и записанные действия
+type Session struct {
+	Seed   int64
+	Config Config
+	// Snapshot - файл снимка, с состояния которого начат сеанс, пустая строка - сеанс начат с init
+	Snapshot string
+	Actions  []Action
+	// initial - прочитанный снимок начального состояния для повторного прогона с начала
+	initial []byte
+	// checkpoints - сохраненные состояния модели для перехода назад, от старых к новым
+	checkpoints []checkpoint
+	// latest - последний пройденный такт, applied - число действий, выполненных к нему
+	latest  int
+	applied int
+	// path - файл, в который сохраняется записываемый сеанс, пустая строка - без записи
+	path string
+	// replay включает воспроизведение: действия берутся из сеанса, а не от пользователя
+	replay bool
+	// next - индекс следующего воспроизводимого действия
+	next int
+	// Paused останавливает такты таймера, модель продвигается только по шагам
+	Paused bool
+}
+
+// randomSource - источник случайных чисел модели, считающий выданные числа,
+// чтобы состояние генератора можно было сохранить в снимке и восстановить
+type randomSource struct {
+	source rand.Source
+	seed   int64
+	draws  int64
+}
+
+// Int63 возвращает следующее случайное число источника
+func (r *randomSource) Int63() int64 {
+	r.draws++
+	return r.source.Int63()
+}
+
+// Seed задает начальное значение источника и сбрасывает счетчик выданных чисел
+func (r *randomSource) Seed(seed int64) {
+	r.source = rand.NewSource(seed)
+	r.seed, r.draws = seed, 0
+}
+
+// restore восстанавливает состояние источника: начальное значение и число выданных чисел
+func (r *randomSource) restore(seed, draws int64) {
+	r.Seed(seed)
+	for r.draws < draws {
+		r.Int63()
+	}
+}
+
+// randomState - источник генератора случайных чисел модели
+var randomState = &randomSource{source: rand.NewSource(1), seed: 1}
+
+// random - генератор случайных чисел модели, его начальное значение задается сеансом
+var random = rand.New(randomState)
+
+var sessionOnce sync.Once
+var sessionInstance *Session
+
+// GetSession предоставляет единственный экземпляр сеанса
+func GetSession() *Session {
+	sessionOnce.Do(func() {
+		sessionInstance = &Session{}
+	})
+
+	return sessionInstance
+}
+
+// Record начинает запись сеанса с текущей конфигурацией и заданным начальным значением генератора
+// Сеанс сохраняется в файл path, если он задан
+func (s *Session) Record(seed int64, path string) {
+	s.Seed, s.Config, s.Actions = seed, *GetConfig(), nil
+	s.path, s.replay = path, false
+	s.next, s.applied, s.latest, s.checkpoints = 0, 0, 0, nil
+	random.Seed(seed)
+}
+
+// LoadSession читает записанный сеанс и начинает его воспроизведение
+// Конфигурация сеанса заменяет текущую, поэтому вызывается до инициализации модели
+func (s *Session) LoadSession(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+
+	*GetConfig() = s.Config
+	if err := GetConfig().Validate(); err != nil {
+		return err
+	}
+	s.path, s.replay = "", true
+	s.next, s.applied, s.latest, s.checkpoints = 0, 0, 0, nil
+	random.Seed(s.Seed)
+	return nil
+}
+
+// Begin восстанавливает начальное состояние сеанса из снимка, если сеанс начат с него
+// Вызывается после инициализации модели
+func (s *Session) Begin() error {
+	if s.Snapshot == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.Snapshot)
+	if err != nil {
+		return err
+	}
+	if err := restoreSnapshot(data); err != nil {
+		return err
+	}
+
+	s.initial = data
+	return nil
+}
+
+// Save записывает сеанс в файл, если он задан
+func (s *Session) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// Replaying сообщает, воспроизводится ли сеанс
+func (s *Session) Replaying() bool {
+	return s.replay
+}
+
+// Recording сообщает, сохраняется ли записываемый сеанс в файл
+func (s *Session) Recording() bool {
+	return s.path != ""
+}
+
+// Do выполняет действие пользователя на текущем такте и добавляет его в сеанс
+// При воспроизведении и просмотре прошлых тактов действия пользователя не принимаются,
+// чтобы прогон совпал с записью
+func (s *Session) Do(a Action) error {
+	if s.replay {
+		return errors.New("при воспроизведении действия не выполняются")
+	}
+	if s.InPast() {
+		return errors.New("действия выполняются только на последнем пройденном такте")
+	}
+
+	a.Tick = GetProcessTable().tick
+	if err := a.Apply(); err != nil {
+		return err
+	}
+
+	s.Actions = append(s.Actions, a)
+	s.next, s.applied = len(s.Actions), len(s.Actions)
+	return nil
+}
+
+// InPast сообщает, показывает ли модель такт раньше последнего пройденного
+func (s *Session) InPast() bool {
+	return GetProcessTable().tick < s.latest
+}
+
+// Latest возвращает последний пройденный такт
+func (s *Session) Latest() int {
+	return s.latest
+}
+
+// applyActions выполняет записанные действия текущего такта, еще не выполненные в этом прогоне
+func (s *Session) applyActions() {
+	tick := GetProcessTable().tick
+	for ; s.next < len(s.Actions) && s.Actions[s.next].Tick <= tick; s.next++ {
+		GetEventLog().repeated = s.next < s.applied
+		// В сеанс попадают только выполненные действия, поэтому при повторе они выполняются так же
+		s.Actions[s.next].Apply()
+	}
+	GetEventLog().repeated = false
+	if s.next > s.applied {
+		s.applied = s.next
+	}
+}
+
+// Step выполняет один такт модели, перед тактом выполняются записанные действия текущего такта
+// Каждые checkpointInterval тактов состояние модели сохраняется для быстрого перехода назад
+func (s *Session) Step() {
+	s.applyActions()
+
+	GetEventLog().repeated = s.InPast()
+	PerformProcess()
+	ScheduleProcess()
+	GetEventLog().repeated = false
+
+	tick := GetProcessTable().tick
+	if tick > s.latest {
+		s.latest = tick
+	}
+	if tick%checkpointInterval == 0 {
+		s.checkpoint()
+	}
+}
+
+// checkpointInterval - период сохранения состояния модели в тактах
+const checkpointInterval = 50
+
+// checkpointCount - наибольшее число хранимых состояний, более старые отбрасываются
+const checkpointCount = 20
+
+// checkpoint - сохраненное состояние модели и число действий сеанса, выполненных к его такту
+type checkpoint struct {
+	tick  int
+	state []byte
+	next  int
+}
+
+// checkpoint сохраняет состояние модели на текущем такте, если оно еще не сохранено
+func (s *Session) checkpoint() {
+	tick := GetProcessTable().tick
+	for _, c := range s.checkpoints {
+		if c.tick == tick {
+			return
+		}
+	}
+
+	state, err := json.Marshal(TakeSnapshot())
+	if err != nil {
+		return
+	}
+
+	// Состояния упорядочены по тактам: повторный прогон после перехода назад сохраняет только недостающие
+	i := sort.Search(len(s.checkpoints), func(i int) bool { return s.checkpoints[i].tick > tick })
+	s.checkpoints = append(s.checkpoints, checkpoint{})
+	copy(s.checkpoints[i+1:], s.checkpoints[i:])
+	s.checkpoints[i] = checkpoint{tick: tick, state: state, next: s.next}
+	if len(s.checkpoints) > checkpointCount {
+		s.checkpoints = s.checkpoints[1:]
+	}
+}
+
+// Seek переходит к такту tick: назад - от ближайшего сохраненного состояния не позже такта
+// или с начала сеанса, если такого нет, затем вперед повтором записанных действий
+// Переход вперед за последний пройденный такт продолжает работу модели
+func (s *Session) Seek(tick int) {
+	if tick < GetProcessTable().tick {
+		s.rewind(tick)
+	}
+
+	for GetProcessTable().tick < tick {
+		s.Step()
+	}
+	s.applyActions()
+}
+
+// rewind восстанавливает ближайшее сохраненное состояние не позже такта tick или начальное состояние сеанса
+func (s *Session) rewind(tick int) {
+	for i := len(s.checkpoints) - 1; i >= 0; i-- {
+		if c := s.checkpoints[i]; c.tick <= tick {
+			// Состояние сохранено самим сеансом, поэтому восстанавливается без ошибок
+			restoreSnapshot(c.state)
+			s.next = c.next
+			return
+		}
+	}
+
+	// Нагрузка конфигурации сеанса и снимок уже создавались при начале сеанса, поэтому восстанавливаются без ошибок
+	*GetConfig() = s.Config
+	random.Seed(s.Seed)
+	ResetModel()
+	s.next = 0
+	if s.initial != nil {
+		restoreSnapshot(s.initial)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// modelState описывает состояние модели строкой для сравнения прогонов
+func modelState() string {
+	pt := GetProcessTable()
+	mmu := GetMMU()
+
+	lines := []string{fmt.Sprintf("такт %d, RAM %d, диск %d", pt.tick, mmu.OccupiedRAM, mmu.OccupiedDisk)}
+	for _, p := range pt.table {
+		lines = append(lines, fmt.Sprintf("%d %s %d %d %d %d %d %v %v",
+			p.PID, p.State.Stringify(), p.CyclesRemains, p.CPUTime, p.Priority, p.CPU, p.Affinity, p.MaxClaim, p.Allocation))
+	}
+	for _, cpu := range pt.cpus {
+		lines = append(lines, fmt.Sprintf("CPU%d %d %d %d", cpu.ID, cpu.roundRobinProcessIndex, cpu.BusyTicks, cpu.Migrations))
+	}
+
+	return strings.Join(append(lines, formatMMU()), "\n")
+}
+
+func TestReplay(t *testing.T) {
+	claimed := ProcessSpec{Name: "claimed", Memory: 4096, Cycles: 300, Priority: 2, MaxClaim: []int{3, 1, 2}, Affinity: 0b10}
+
+	tests := []struct {
+		name   string
+		seed   int64
+		ticks  int
+		script map[int][]Action
+	}{
+		{"случайные процессы", 1, 200, map[int][]Action{
+			0:  {{Kind: ActionCreateRandom, Value: 5}},
+			40: {{Kind: ActionCreateRandom, Value: 5}},
+		}},
+		{"сигналы и блокировка", 2, 200, map[int][]Action{
+			0:  {{Kind: ActionCreateRandom, Value: 6}},
+			30: {{Kind: ActionSignal, PID: 2, Value: int(SigKill)}, {Kind: ActionBlock, PID: 3}},
+			40: {{Kind: ActionSignal, PID: 4, Value: int(SigStop)}},
+			60: {{Kind: ActionUnblock, PID: 3}, {Kind: ActionPriority, PID: 5, Value: 3}},
+			70: {{Kind: ActionSignal, PID: 4, Value: int(SigCont)}},
+		}},
+		{"изменение конфигурации", 3, 200, map[int][]Action{
+			0:  {{Kind: ActionCreateRandom, Value: 4}},
+			25: {{Kind: ActionConfig, Key: "PerCPUQueues", Value: 1}},
+			50: {{Kind: ActionConfig, Key: "KernelThreads", Value: 0}, {Kind: ActionCreateRandom, Value: 2}},
+		}},
+		{"заданные параметры процесса", 4, 150, map[int][]Action{
+			0:  {{Kind: ActionCreate, Spec: &claimed}},
+			10: {{Kind: ActionCreateRandom, Value: 3}, {Kind: ActionAffinity, PID: 1}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.json")
+
+			record := startModel(t, test.seed, nil)
+			record.path = path
+
+			states := make([]string, 0, test.ticks)
+			for i := 0; i < test.ticks; i++ {
+				for _, a := range test.script[i] {
+					if err := record.Do(a); err != nil {
+						t.Fatalf("такт %d: действие %s: %v", i, a.Kind, err)
+					}
+				}
+				states = append(states, modelState())
+				record.Step()
+			}
+			if err := record.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			replay := &Session{}
+			sessionInstance = replay
+			if err := replay.LoadSession(path); err != nil {
+				t.Fatal(err)
+			}
+			ResetModel()
+
+			for i, want := range states {
+				replay.applyActions()
+				if got := modelState(); got != want {
+					t.Fatalf("такт %d: состояние воспроизведения отличается от записи\n%s\n---\n%s", i, got, want)
+				}
+				replay.Step()
+			}
+		})
+	}
+}
+
+// snapshotState возвращает снимок текущего состояния модели в JSON
+func snapshotState(t *testing.T) string {
+	t.Helper()
+
+	data, err := json.Marshal(TakeSnapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestRewind(t *testing.T) {
+	const ticks = 300
+	script := map[int][]Action{
+		0:   {{Kind: ActionCreateRandom, Value: 6}},
+		20:  {{Kind: ActionSignal, PID: 2, Value: int(SigStop)}, {Kind: ActionBlock, PID: 3}},
+		70:  {{Kind: ActionSignal, PID: 2, Value: int(SigCont)}, {Kind: ActionUnblock, PID: 3}},
+		120: {{Kind: ActionCreateRandom, Value: 4}, {Kind: ActionConfig, Key: "PerCPUQueues", Value: 1}},
+		210: {{Kind: ActionSignal, PID: 8, Value: int(SigKill)}},
+	}
+
+	session := startModel(t, 5, nil)
+	states := make([]string, 0, ticks+1)
+	for i := 0; i <= ticks; i++ {
+		for _, a := range script[i] {
+			if err := session.Do(a); err != nil {
+				t.Fatalf("такт %d: действие %s: %v", i, a.Kind, err)
+			}
+		}
+		states = append(states, snapshotState(t))
+		if i < ticks {
+			session.Step()
+		}
+	}
+
+	tests := []struct {
+		from, back int
+	}{
+		{300, 1},
+		{300, 300},
+		{250, 50},
+		{130, 17},
+		{75, 75},
+		{40, 1},
+		{300, 149},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("с такта %d назад на %d", test.from, test.back), func(t *testing.T) {
+			session.Seek(test.from)
+			session.Seek(test.from - test.back)
+			if got := snapshotState(t); got != states[test.from-test.back] {
+				t.Fatalf("после перехода назад состояние такта %d отличается от записанного", test.from-test.back)
+			}
+
+			for i := 0; i < test.back; i++ {
+				session.Step()
+			}
+			session.applyActions()
+			if got := snapshotState(t); got != states[test.from] {
+				t.Fatalf("после %d тактов вперед состояние такта %d отличается от записанного", test.back, test.from)
+			}
+		})
+	}
+}
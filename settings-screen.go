@@ -0,0 +1,154 @@
+package main
+
+import (
+	"strconv"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// settingsScreen - экран настроек модели, изменения применяются сразу
+type settingsScreen struct {
+	checkboxes []*uitools.Checkbox
+	flags      []*bool
+	inputs     []*uitools.TextInput
+	values     []*int
+	layout     *uitools.Layout
+	events     *uitools.EventNode
+	message    string
+}
+
+// newSettingsScreen создает экран настроек модели
+func newSettingsScreen() *settingsScreen {
+	theme := uitools.CurrentTheme
+	config := GetConfig()
+	s := &settingsScreen{}
+
+	// Параметры меняются действиями сеанса по имени поля конфигурации, чтобы изменения попали в запись
+	flags := []struct {
+		caption string
+		key     string
+		value   *bool
+	}{
+		{"Алгоритм банкира", "BankerMode", &config.BankerMode},
+		{"Отдельные очереди процессоров", "PerCPUQueues", &config.PerCPUQueues},
+		{"Жесткая привязка к процессорам", "HardAffinity", &config.HardAffinity},
+		{"Потоки уровня ядра", "KernelThreads", &config.KernelThreads},
+		{"Хранить показатели всех тактов", "KeepAllSamples", &config.KeepAllSamples},
+	}
+
+	for _, f := range flags {
+		caption, key, value := f.caption, f.key, f.value
+		s.checkboxes = append(s.checkboxes, uitools.NewCheckbox(0, 0, caption, *value, theme.Normal.Fg, theme.Normal.Bg,
+			func(c *uitools.Checkbox) {
+				checked := 0
+				if c.Checked() {
+					checked = 1
+				}
+				if err := GetSession().Do(Action{Kind: ActionConfig, Key: key, Value: checked}); err != nil {
+					s.message = err.Error()
+					c.SetChecked(*value)
+					return
+				}
+				s.message = ""
+			}))
+		s.flags = append(s.flags, value)
+	}
+
+	numbers := []struct {
+		caption string
+		key     string
+		value   *int
+	}{
+		{"Стоимость переключения контекста", "ContextSwitchCost", &config.ContextSwitchCost},
+		{"Штраф за миграцию", "MigrationPenalty", &config.MigrationPenalty},
+		{"Интервал балансировки", "BalanceInterval", &config.BalanceInterval},
+	}
+
+	// Числовые параметры выводятся сеткой: подпись и поле ввода
+	grid := uitools.NewGridLayout(2, 1)
+	for _, n := range numbers {
+		caption, key, value := n.caption, n.key, n.value
+		input := uitools.NewTextInput(0, 0, 8, strconv.Itoa(*value), theme.Normal.Fg, theme.Normal.Bg,
+			func(t *uitools.TextInput) {
+				// Значение принимается по Enter, некорректное значение восстанавливается из конфигурации
+				number, err := strconv.Atoi(t.Text())
+				if err != nil || number < 0 {
+					s.message = "Некорректное значение: " + caption
+					t.SetText(strconv.Itoa(*value))
+					return
+				}
+				if err := GetSession().Do(Action{Kind: ActionConfig, Key: key, Value: number}); err != nil {
+					s.message = err.Error()
+					t.SetText(strconv.Itoa(*value))
+					return
+				}
+				s.message = caption + ": " + strconv.Itoa(number)
+			})
+		s.inputs = append(s.inputs, input)
+		s.values = append(s.values, value)
+		grid.Add(uitools.NewLabel(0, 0, caption, theme.Normal.Fg, theme.Normal.Bg)).Add(input)
+	}
+
+	s.layout = uitools.NewVerticalLayout(0)
+	focusable := []uitools.Widget{}
+	for _, c := range s.checkboxes {
+		s.layout.Add(c)
+		focusable = append(focusable, c)
+	}
+	s.layout.AddSpacer().Add(grid)
+	for _, t := range s.inputs {
+		focusable = append(focusable, t)
+	}
+
+	focus := uitools.NewFocusGroup(focusable...)
+	focus.Focus(s.checkboxes[0])
+
+	s.events = uitools.NewEventNode()
+	s.events.AddWidget(focus)
+	s.events.On(uitools.ResizeEvent, func(ev *termbox.Event) bool {
+		s.layout.Arrange(1, 3, ev.Width-1, ev.Height-4)
+		return false
+	})
+
+	s.layout.Arrange(1, 3, screenWidth-1, screenHeight-4)
+	return s
+}
+
+// Title возвращает название экрана
+func (s *settingsScreen) Title() string {
+	return "Настройки"
+}
+
+// Enter обновляет флажки и поля ввода значениями из конфигурации
+func (s *settingsScreen) Enter() {
+	for i, c := range s.checkboxes {
+		c.SetChecked(*s.flags[i])
+	}
+	for i, t := range s.inputs {
+		t.SetText(strconv.Itoa(*s.values[i]))
+	}
+	s.message = ""
+}
+
+// Leave вызывается при уходе с экрана
+func (s *settingsScreen) Leave() {}
+
+// HandleEvent передает событие элементам экрана
+func (s *settingsScreen) HandleEvent(ev *termbox.Event) bool {
+	return s.events.Dispatch(ev)
+}
+
+// Draw отображает элементы настройки и результат последнего изменения
+func (s *settingsScreen) Draw() {
+	theme := uitools.CurrentTheme
+
+	uitools.Print(0, 1, theme.Normal.Fg, theme.Normal.Bg, "Настройки модели")
+	s.layout.Draw()
+
+	drawStatusBar()
+	if s.message != "" {
+		uitools.Print(statusBarX+1, statusBarY, theme.Status.Fg, theme.Status.Bg, s.message)
+	}
+}
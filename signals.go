@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SendSignal ставит сигнал в очередь процесса, сигналы доставляются диспетчером в начале такта
+func (pt *ProcessTable) SendSignal(process *Process, signal Signal) error {
+	if process.GID == 1 {
+		return errors.New("процесс init не принимает сигналы")
+	}
+	if !pt.contains(process) {
+		return fmt.Errorf("процесс %d уже завершен", process.PID)
+	}
+
+	process.PendingSignals = append(process.PendingSignals, signal)
+	return nil
+}
+
+// deliverSignals доставляет процессам ожидающие сигналы в порядке их отправки
+func (pt *ProcessTable) deliverSignals() {
+	// Завершение процесса меняет таблицу, поэтому обход идет по копии
+	processes := append([]*Process(nil), pt.table...)
+	for _, p := range processes {
+		signals := p.PendingSignals
+		p.PendingSignals = nil
+
+		for _, signal := range signals {
+			switch signal {
+			case SigKill:
+				GetEventLog().Log(EventKilled, p.PID, -1, "")
+				pt.terminate(p)
+			case SigStop:
+				pt.suspend(p)
+			case SigCont:
+				pt.resume(p)
+			}
+
+			if p.State == Terminated {
+				break
+			}
+		}
+	}
+}
+
+// suspend приостанавливает процесс, освобождая его процессоры и выгружая сегмент памяти на диск
+// При переполненном диске сегмент остается в оперативной памяти
+func (pt *ProcessTable) suspend(process *Process) {
+	if process.State == Suspended {
+		return
+	}
+
+	pt.releaseCPUs(process)
+	for _, t := range process.Threads {
+		if t.State == Execution {
+			t.State = Readiness
+		}
+	}
+
+	if process.MemoryBlock != nil {
+		swapOut(process, -1)
+	}
+
+	process.IOWaitRemains = 0
+	process.SetState(Suspended)
+	GetEventLog().Log(EventSuspended, process.PID, -1, "")
+}
+
+// resume возвращает приостановленный процесс в очередь готовых, память загружается при следующем выборе процесса
+func (pt *ProcessTable) resume(process *Process) {
+	if process.State == Suspended {
+		process.SetState(Readiness)
+		GetEventLog().Log(EventResumed, process.PID, -1, "")
+	}
+}
+
+// SetPriority меняет приоритет процесса и сбрасывает кванты процесса и его потоков к начальному для нового приоритета
+func (pt *ProcessTable) SetPriority(process *Process, priority int) error {
+	if process.GID == 1 {
+		return errors.New("приоритет процесса init не меняется")
+	}
+	if priority < 0 || priority > MaxPriority {
+		return fmt.Errorf("приоритет должен быть от 0 до %d", MaxPriority)
+	}
+
+	process.Priority = priority
+	process.TimeSlot = 1 << uint(priority)
+	for _, t := range process.Threads {
+		if t.State != Terminated {
+			t.TimeSlot = process.TimeSlot
+		}
+	}
+
+	return nil
+}
+
+// SetQuantum задает текущий квант времени процесса и его незавершенных потоков, дальше кванты растут как обычно
+func (pt *ProcessTable) SetQuantum(process *Process, quantum int) error {
+	if process.GID == 1 {
+		return errors.New("квант процесса init не меняется")
+	}
+	if quantum < 1 {
+		return errors.New("квант должен быть положительным")
+	}
+
+	process.TimeSlot = quantum
+	for _, t := range process.Threads {
+		if t.State != Terminated {
+			t.TimeSlot = quantum
+		}
+	}
+
+	return nil
+}
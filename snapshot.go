@@ -0,0 +1,253 @@
+package main
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Snapshot - полное состояние модели: таблица процессов с указателями Round-Robin, процессоры,
+// список блоков памяти, выгруженные на диск сегменты, ресурсы, конфигурация и генератор случайных чисел
+// Указатели в снимке заменяются номерами: сегмент процесса - номером узла в списке блоков,
+// процессы процессоров - номерами в таблице процессов, потоки - номерами в списке потоков процесса
+type Snapshot struct {
+	Tick           int
+	ProcessCounter int
+	ThreadCounter  int
+	Processes      []processSnapshot
+	Finished       []*Process
+	CPUs           []cpuSnapshot
+	Samples        []TickSample
+	Blocks         []MemoryBlockNode
+	Memory         MemoryManagementUnit
+	Resources      ResourceManager
+	Config         Config
+	// Seed и Draws - начальное значение генератора случайных чисел и число выданных им чисел
+	Seed  int64
+	Draws int64
+}
+
+// processSnapshot - процесс в снимке
+type processSnapshot struct {
+	*Process
+	ThreadIndex int
+	// Block - номер сегмента процесса в списке блоков памяти, -1 если сегмента нет
+	Block int
+}
+
+// cpuSnapshot - процессор в снимке, процессы и потоки заданы номерами, -1 вместо отсутствующих
+type cpuSnapshot struct {
+	*CPU
+	RoundRobinIndex int
+	Process         int
+	Thread          int
+	LastProcess     int
+	StallRemains    int
+	Pinned          bool
+}
+
+// indexOf возвращает номер процесса в таблице или -1
+func (pt *ProcessTable) indexOf(process *Process) int {
+	for i, p := range pt.table {
+		if p == process {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// TakeSnapshot снимает состояние модели
+func TakeSnapshot() *Snapshot {
+	pt := GetProcessTable()
+	mmu := GetMMU()
+	s := &Snapshot{Tick: pt.tick,
+		ProcessCounter: pt.processCounter,
+		ThreadCounter:  pt.threadCounter,
+		Finished:       pt.finished,
+		Samples:        pt.Samples,
+		Memory:         *mmu,
+		Resources:      *GetResourceManager(),
+		Config:         *GetConfig(),
+		Seed:           randomState.seed,
+		Draws:          randomState.draws}
+
+	blocks := map[*MemoryBlockNode]int{}
+	for e := mmu.blockList.Front(); e != nil; e = e.Next() {
+		node := e.Value.(*MemoryBlockNode)
+		blocks[node] = len(s.Blocks)
+		s.Blocks = append(s.Blocks, *node)
+	}
+
+	for _, p := range pt.table {
+		block := -1
+		if p.MemoryBlock != nil {
+			block = blocks[p.MemoryBlock]
+		}
+		s.Processes = append(s.Processes, processSnapshot{Process: p, ThreadIndex: p.threadIndex, Block: block})
+	}
+
+	for _, cpu := range pt.cpus {
+		thread := -1
+		if cpu.currentProcess != nil {
+			for i, t := range cpu.currentProcess.Threads {
+				if t == cpu.currentThread {
+					thread = i
+				}
+			}
+		}
+		// Последний загруженный процесс мог завершиться и покинуть таблицу, тогда он не сохраняется:
+		// ни один процесс таблицы с ним не совпадет, как и с отсутствующим
+		s.CPUs = append(s.CPUs, cpuSnapshot{CPU: cpu,
+			RoundRobinIndex: cpu.roundRobinProcessIndex,
+			Process:         pt.indexOf(cpu.currentProcess),
+			Thread:          thread,
+			LastProcess:     pt.indexOf(cpu.lastProcess),
+			StallRemains:    cpu.stallRemains,
+			Pinned:          cpu.pinned})
+	}
+
+	return s
+}
+
+// Restore заменяет состояние модели состоянием из снимка, связывая процессы с сегментами
+// восстановленного списка блоков памяти
+// Журнал событий сохраняет только события, произошедшие до такта снимка
+// Модель получает объекты прочитанного снимка, поэтому каждый прочитанный снимок восстанавливается один раз
+func (s *Snapshot) Restore() error {
+	if err := s.Config.Validate(); err != nil {
+		return fmt.Errorf("снимок поврежден: %v", err)
+	}
+	if len(s.CPUs) != s.Config.CPUCount {
+		return fmt.Errorf("снимок поврежден: %d процессоров при CPUCount %d", len(s.CPUs), s.Config.CPUCount)
+	}
+
+	blocks := make([]*MemoryBlockNode, len(s.Blocks))
+	blockList := list.New()
+	for i := range s.Blocks {
+		node := s.Blocks[i]
+		blocks[i] = &node
+		blockList.PushBack(&node)
+	}
+
+	index := func(i, length int, what string) error {
+		if i < -1 || i >= length {
+			return fmt.Errorf("снимок поврежден: неверный номер %s %d", what, i)
+		}
+		return nil
+	}
+
+	pt := &ProcessTable{tick: s.Tick, processCounter: s.ProcessCounter, threadCounter: s.ThreadCounter, Samples: s.Samples}
+	for _, p := range s.Finished {
+		if p == nil {
+			return errors.New("снимок поврежден: пустая запись процесса")
+		}
+		for _, t := range p.Threads {
+			t.Parent = p
+		}
+		pt.finished = append(pt.finished, p)
+	}
+	for _, ps := range s.Processes {
+		if ps.Process == nil {
+			return errors.New("снимок поврежден: пустая запись процесса")
+		}
+		if err := index(ps.Block, len(blocks), "сегмента"); err != nil {
+			return err
+		}
+
+		p := ps.Process
+		p.threadIndex = ps.ThreadIndex
+		p.MemoryBlock = nil
+		if ps.Block != -1 {
+			p.MemoryBlock = blocks[ps.Block]
+		}
+		for _, t := range p.Threads {
+			t.Parent = p
+		}
+		pt.table = append(pt.table, p)
+	}
+
+	for _, cs := range s.CPUs {
+		if cs.CPU == nil {
+			return errors.New("снимок поврежден: пустая запись процессора")
+		}
+		if err := index(cs.Process, len(pt.table), "процесса"); err != nil {
+			return err
+		}
+		if err := index(cs.LastProcess, len(pt.table), "процесса"); err != nil {
+			return err
+		}
+		if cs.RoundRobinIndex < 0 || cs.RoundRobinIndex >= len(pt.table) {
+			return fmt.Errorf("снимок поврежден: неверный индекс Round-Robin %d", cs.RoundRobinIndex)
+		}
+
+		cpu := cs.CPU
+		cpu.roundRobinProcessIndex = cs.RoundRobinIndex
+		cpu.currentProcess, cpu.currentThread, cpu.lastProcess = nil, nil, nil
+		if cs.Process != -1 {
+			cpu.currentProcess = pt.table[cs.Process]
+			if err := index(cs.Thread, len(cpu.currentProcess.Threads), "потока"); err != nil {
+				return err
+			}
+			if cs.Thread != -1 {
+				cpu.currentThread = cpu.currentProcess.Threads[cs.Thread]
+			}
+		}
+		if cs.LastProcess != -1 {
+			cpu.lastProcess = pt.table[cs.LastProcess]
+		}
+		cpu.stallRemains, cpu.pinned = cs.StallRemains, cs.Pinned
+		pt.cpus = append(pt.cpus, cpu)
+	}
+
+	mmu := s.Memory
+	mmu.blockList = blockList
+	resources := s.Resources
+
+	// Единственные экземпляры создаются до замены, чтобы отложенная инициализация не перезаписала их
+	GetProcessTable()
+	GetMMU()
+	GetResourceManager()
+	tableInstance, mmuInstance, resourceManagerInstance = pt, &mmu, &resources
+	*GetConfig() = s.Config
+	randomState.restore(s.Seed, s.Draws)
+
+	log := GetEventLog()
+	for len(log.Events) > 0 && log.Events[len(log.Events)-1].Tick > s.Tick {
+		log.Events = log.Events[:len(log.Events)-1]
+	}
+
+	return nil
+}
+
+// SaveSnapshot записывает состояние модели в файл в формате JSON
+func SaveSnapshot(path string) error {
+	data, err := json.Marshal(TakeSnapshot())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSnapshot восстанавливает состояние модели из файла
+func LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return restoreSnapshot(data)
+}
+
+// restoreSnapshot восстанавливает состояние модели из снимка в формате JSON
+func restoreSnapshot(data []byte) error {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+
+	return s.Restore()
+}
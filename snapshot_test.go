@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// advance выполняет ticks тактов модели, добавляя случайный процесс каждые четыре такта
+func advance(ticks int) {
+	pt := GetProcessTable()
+	for i := 0; i < ticks; i++ {
+		if pt.tick%4 == 0 {
+			pt.AddProcess()
+		}
+		PerformProcess()
+		ScheduleProcess()
+	}
+}
+
+// checkLinks проверяет, что указатели восстановленной модели ссылаются на ее же объекты
+func checkLinks(t *testing.T) {
+	t.Helper()
+
+	blocks := map[*MemoryBlockNode]bool{}
+	for e := GetMMU().blockList.Front(); e != nil; e = e.Next() {
+		blocks[e.Value.(*MemoryBlockNode)] = true
+	}
+
+	pt := GetProcessTable()
+	for _, p := range pt.table {
+		if p.MemoryBlock != nil && !blocks[p.MemoryBlock] {
+			t.Fatalf("сегмент процесса %d отсутствует в списке блоков памяти", p.PID)
+		}
+		for _, th := range p.Threads {
+			if th.Parent != p {
+				t.Fatalf("поток %d ссылается не на свой процесс %d", th.TID, p.PID)
+			}
+		}
+	}
+	for _, cpu := range pt.cpus {
+		if cpu.currentProcess != nil && !pt.contains(cpu.currentProcess) {
+			t.Fatalf("текущий процесс CPU%d отсутствует в таблице", cpu.ID)
+		}
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *Config)
+		seed   int64
+	}{
+		{"по умолчанию", nil, 1},
+		{"алгоритм банкира", func(c *Config) { c.BankerMode = true }, 2},
+		{"раздельные очереди с привязкой", func(c *Config) { c.PerCPUQueues, c.HardAffinity = true, true }, 3},
+		{"потоки уровня пользователя", func(c *Config) { c.KernelThreads = false }, 4},
+		{"четыре процессора, best-fit", func(c *Config) { c.CPUCount, c.Placement = 4, BestFit }, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, test.seed, test.config)
+
+			path := filepath.Join(t.TempDir(), "snapshot.json")
+			advance(300)
+			saved := modelState()
+			if err := SaveSnapshot(path); err != nil {
+				t.Fatal(err)
+			}
+			advance(200)
+			want := modelState()
+
+			if err := LoadSnapshot(path); err != nil {
+				t.Fatal(err)
+			}
+			if got := modelState(); got != saved {
+				t.Fatalf("восстановленное состояние отличается от сохраненного\n%s\n---\n%s", got, saved)
+			}
+			checkLinks(t)
+
+			advance(200)
+			if got := modelState(); got != want {
+				t.Fatalf("прогон после восстановления отличается от исходного\n%s\n---\n%s", got, want)
+			}
+			checkLinks(t)
+		})
+	}
+}
+
+func TestRestoreCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(s *Snapshot)
+	}{
+		{"неизвестная стратегия размещения", func(s *Snapshot) { s.Config.Placement = "next-fit" }},
+		{"нет процессоров", func(s *Snapshot) { s.Config.CPUCount, s.CPUs = 0, nil }},
+		{"число процессоров не совпадает", func(s *Snapshot) { s.CPUs = s.CPUs[:1] }},
+		{"недопустимая нагрузка", func(s *Snapshot) {
+			s.Config.Workload = []ProcessSpec{{Name: "p", Memory: 1, Cycles: 1, MaxClaim: []int{1}}}
+		}},
+		{"отрицательный индекс Round-Robin", func(s *Snapshot) { s.CPUs[1].RoundRobinIndex = -1 }},
+		{"индекс Round-Robin за концом таблицы", func(s *Snapshot) { s.CPUs[0].RoundRobinIndex = len(s.Processes) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startModel(t, 1, func(c *Config) { c.CPUCount = 2 })
+			advance(100)
+
+			snapshot := TakeSnapshot()
+			test.corrupt(snapshot)
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := modelState()
+			if err := restoreSnapshot(data); err == nil {
+				t.Fatal("поврежденный снимок восстановлен без ошибки")
+			}
+			if got := modelState(); got != want {
+				t.Fatalf("поврежденный снимок изменил состояние модели\n%s\n---\n%s", got, want)
+			}
+		})
+	}
+}
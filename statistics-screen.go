@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+// statisticsScreen - экран накопленной статистики процессоров и процессов
+type statisticsScreen struct {
+	// message - результат последней выгрузки статистики
+	message string
+}
+
+// Title возвращает название экрана
+func (s *statisticsScreen) Title() string {
+	return "Статистика"
+}
+
+// Enter вызывается при переходе на экран
+func (s *statisticsScreen) Enter() {}
+
+// Leave вызывается при уходе с экрана
+func (s *statisticsScreen) Leave() {}
+
+// HandleEvent выгружает статистику в файлы CSV и JSON по 'e'
+func (s *statisticsScreen) HandleEvent(ev *termbox.Event) bool {
+	if ev.Type != termbox.EventKey || ev.Ch != 'e' {
+		return false
+	}
+
+	paths, err := ExportStats(fmt.Sprintf("stats-%d", GetProcessTable().tick))
+	if err != nil {
+		s.message = err.Error()
+	} else {
+		s.message = exportSummary(paths)
+	}
+	return true
+}
+
+// Draw отображает статистику
+func (s *statisticsScreen) Draw() {
+	theme := uitools.CurrentTheme
+
+	uitools.Print(0, 1, theme.Normal.Fg, theme.Normal.Bg, "Статистика (e - выгрузить в CSV и JSON)")
+	drawStatistics(0, 3)
+
+	drawStatusBar()
+	if s.message != "" {
+		uitools.Print(statusBarX+1, statusBarY, theme.Status.Fg, theme.Status.Bg, s.message)
+	}
+}
+
+// drawStatistics отображает накопленную статистику процессоров и процессов в заданной области
+func drawStatistics(x, y int) {
+	pt := GetProcessTable()
+	theme := uitools.CurrentTheme
+
+	uitools.Printf(x, y, theme.Normal.Fg, theme.Normal.Bg, "%-5s %8s %8s %10s %8s %8s %12s", "CPU", "Загрузка", "Работа", "Накл.расх.", "Простой", "Миграции", "Переключения")
+
+	busy, overhead, idle, switches := 0, 0, 0, 0
+	for i, cpu := range pt.cpus {
+		uitools.Printf(x, y+1+i, theme.Normal.Fg, theme.Normal.Bg, "%-5d %7d%% %8d %10d %8d %8d %12d",
+			cpu.ID, cpu.Utilization(), cpu.BusyTicks, cpu.OverheadTicks, cpu.IdleTicks(), cpu.Migrations, cpu.ContextSwitches)
+		busy += cpu.BusyTicks
+		overhead += cpu.OverheadTicks
+		idle += cpu.IdleTicks()
+		switches += cpu.ContextSwitches
+	}
+
+	// Итоговый учет: полезная работа, накладные расходы и простой по всем процессорам
+	row := y + 1 + len(pt.cpus)
+	uitools.Printf(x, row, theme.Normal.Fg, theme.Normal.Bg, "Итого: работа %d, накладные расходы %d (переключений контекста %d, стоимость %d), простой %d",
+		busy, overhead, switches, GetConfig().ContextSwitchCost, idle)
+
+	// Фрагментация оперативной памяти
+	f := GetMMU().Fragmentation()
+	row++
+	uitools.Printf(x, row, theme.Normal.Fg, theme.Normal.Bg, "Память: пустых сегментов %d, средний %.0f, наибольший %d из %d свободных, внешняя фрагментация %.0f%%, неудачных выделений %d",
+		f.Holes, f.MeanHole, f.LargestHole, f.FreeRAM, f.External*100, f.FailedAllocations)
+
+	row += 2
+	uitools.Printf(x, row, theme.Normal.Fg, theme.Normal.Bg, "%5s %-12s %-10s %8s %12s %8s %8s", "PID", "Имя", "Привязка", "Миграции", "Переключения", "Ожидание", "Выгрузки")
+	for _, p := range pt.table {
+		if p.GID == 1 {
+			continue
+		}
+		row++
+		uitools.Printf(x, row, theme.Normal.Fg, theme.Normal.Bg, "%5d %-12s %-10b %8d %12d %8d %8d", p.PID, p.Name, p.Affinity, p.Migrations, p.ContextSwitches, p.WaitTicks, p.Swaps)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"operating-systems/processes/uitools"
+)
+
+// threadTableColumns - столбцы таблицы потоков
+var threadTableColumns = []uitools.Column{
+	{Title: "TID", Width: 5},
+	{Title: "Состояние", Width: 13},
+	{Title: "Осталось тактов", Width: 15},
+	{Title: "Время CPU", Width: 9},
+	{Title: "Квант", Width: 7},
+	{Title: "CPU", Width: 3},
+}
+
+// Thread представляет поток процесса, разделяющий с ним сегмент памяти
+type Thread struct {
+	TID           int
+	State         ProcessState
+	CyclesRemains int
+	TimeSlot      int
+	CPUTime       int
+	// Parent - процесс потока, в снимке восстанавливается по списку потоков процесса
+	Parent *Process `json:"-"`
+}
+
+// MemoryBlock возвращает сегмент памяти родительского процесса
+func (t *Thread) MemoryBlock() *MemoryBlockNode {
+	return t.Parent.MemoryBlock
+}
+
+// runningOn возвращает процессор, исполняющий поток на текущем такте, или nil
+func (t *Thread) runningOn() *CPU {
+	for _, cpu := range GetProcessTable().cpus {
+		if cpu.currentThread == t {
+			return cpu
+		}
+	}
+
+	return nil
+}
+
+// nextThread выбирает по схеме Round-Robin незавершенный поток процесса, не занятый другим процессором, или nil
+func (p *Process) nextThread(cpu *CPU) *Thread {
+	for i := 0; i < len(p.Threads); i++ {
+		index := (p.threadIndex + i) % len(p.Threads)
+		t := p.Threads[index]
+		if t.State == Terminated {
+			continue
+		}
+		if owner := t.runningOn(); owner != nil && owner != cpu {
+			continue
+		}
+
+		p.threadIndex = index + 1
+		return t
+	}
+
+	return nil
+}
+
+// hasFreeThread проверяет, есть ли у процесса незавершенный поток, не занятый ни одним процессором
+func (p *Process) hasFreeThread() bool {
+	for _, t := range p.Threads {
+		if t.State != Terminated && t.runningOn() == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ThreadRows формирует строки таблицы потоков процесса для отображения
+func (p *Process) ThreadRows() [][]string {
+	rows := make([][]string, len(p.Threads))
+	for i, t := range p.Threads {
+		cpu := "-"
+		if owner := t.runningOn(); owner != nil {
+			cpu = fmt.Sprint(owner.ID)
+		}
+		rows[i] = []string{fmt.Sprint(t.TID), t.State.Stringify(), fmt.Sprint(t.CyclesRemains), fmt.Sprint(t.CPUTime), fmt.Sprint(t.TimeSlot), cpu}
+	}
+
+	return rows
+}
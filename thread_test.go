@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestThreadCreation(t *testing.T) {
+	tests := []struct {
+		maxThreads int
+		cycles     int
+	}{
+		{1, 100},
+		{3, 100},
+		{8, 1000},
+		{8, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("потоков до %d, тактов %d", test.maxThreads, test.cycles), func(t *testing.T) {
+			startModel(t, 6, func(c *Config) { c.MaxThreads = test.maxThreads })
+			pt := GetProcessTable()
+			for i := 0; i < 20; i++ {
+				if err := pt.CreateProcess(ProcessSpec{Name: "p", Memory: 10, Cycles: test.cycles}); err != nil {
+					t.Fatal(err)
+				}
+				p := pt.table[len(pt.table)-1]
+
+				if n := len(p.Threads); n < 1 || n > test.maxThreads || n > test.cycles {
+					t.Fatalf("процесс %d: потоков %d", p.PID, n)
+				}
+				cycles := 0
+				for _, th := range p.Threads {
+					if th.Parent != p {
+						t.Fatalf("поток %d ссылается не на свой процесс", th.TID)
+					}
+					if th.CyclesRemains < 1 {
+						t.Fatalf("поток %d без тактов", th.TID)
+					}
+					cycles += th.CyclesRemains
+				}
+				if cycles != test.cycles {
+					t.Fatalf("процесс %d: такты потоков %d, ожидалось %d", p.PID, cycles, test.cycles)
+				}
+			}
+		})
+	}
+}
+
+func TestKernelThreads(t *testing.T) {
+	tests := []struct {
+		name     string
+		kernel   bool
+		parallel bool
+	}{
+		{"потоки ядра", true, true},
+		{"потоки пользователя", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := startModel(t, 7, func(c *Config) {
+				c.CPUCount, c.PerCPUQueues, c.MaxThreads, c.KernelThreads = 4, false, 4, test.kernel
+			})
+			pt := GetProcessTable()
+			for i := 0; i < 2; i++ {
+				if err := pt.CreateProcess(ProcessSpec{Name: "p", Memory: 10, Cycles: 400}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			parallel := false
+			runToCompletion(t, session, 30000, func() {
+				running := map[*Process]*Thread{}
+				for _, cpu := range pt.cpus {
+					p := cpu.currentProcess
+					if p == nil || p.GID == 1 || cpu.currentThread == nil {
+						continue
+					}
+					if th, ok := running[p]; ok {
+						parallel = true
+						if th == cpu.currentThread {
+							t.Fatalf("такт %d: поток %d исполняется на двух процессорах", pt.tick, th.TID)
+						}
+					}
+					running[p] = cpu.currentThread
+				}
+			})
+
+			if parallel != test.parallel {
+				t.Fatalf("процесс исполнялся на нескольких процессорах одновременно: %v, ожидалось %v", parallel, test.parallel)
+			}
+		})
+	}
+}
@@ -1,15 +1,134 @@
 package main
 
-import "github.com/nsf/termbox-go"
+import (
+	"fmt"
+	"strings"
 
-const (
+	"operating-systems/processes/uitools"
+
+	"github.com/nsf/termbox-go"
+)
+
+const statusBarX = 0
+
+// Размеры экрана и строки состояния, пересчитываются при изменении размера терминала
+var (
+	screenWidth    = 120
+	screenHeight   = 30
 	statusBarWidth = 120
-	statusBarX     = 0
 	statusBarY     = 29
 )
 
+// Запоминает новый размер экрана и переносит строку состояния в последнюю строку
+func resizeScreen(width, height int) {
+	screenWidth, screenHeight = width, height
+	statusBarWidth = width
+	statusBarY = height - 1
+}
+
+// Рисует строку состояния, справа в ней выводится режим сеанса
 func drawStatusBar() {
+	theme := uitools.CurrentTheme
 	for i := 0; i < statusBarWidth; i++ {
-		termbox.SetCell(statusBarX+i, statusBarY, '▓', termbox.ColorWhite, termbox.ColorWhite)
+		termbox.SetCell(statusBarX+i, statusBarY, '▓', theme.Status.Bg, theme.Status.Bg)
+	}
+
+	if text := sessionStatus(); text != "" {
+		uitools.Print(statusBarX+statusBarWidth-len([]rune(text))-1, statusBarY, theme.Status.Fg, theme.Status.Bg, text)
+	}
+}
+
+// snapshotMessage - результат последнего сохранения снимка для строки состояния
+var snapshotMessage string
+
+// Возвращает описание режима сеанса: запись, воспроизведение и пауза
+func sessionStatus() string {
+	session := GetSession()
+	parts := []string{}
+	if snapshotMessage != "" {
+		parts = append(parts, snapshotMessage)
+	}
+	if session.Recording() {
+		parts = append(parts, "ЗАПИСЬ")
+	}
+	if session.Replaying() {
+		parts = append(parts, fmt.Sprintf("ВОСПР. такт %d", GetProcessTable().tick))
+	} else if session.InPast() {
+		parts = append(parts, fmt.Sprintf("ИСТОРИЯ такт %d из %d", GetProcessTable().tick, session.Latest()))
+	}
+	if session.Paused {
+		parts = append(parts, "ПАУЗА")
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// Управляет сеансом с клавиатуры: 'p' - пауза, '.' - шаг на паузе, 's' - сохранение снимка модели,
+// ',' - шаг назад, '[' и ']' - переход на 10 тактов назад и вперед, '{' и '}' - на 100 тактов
+// Переход назад ставит модель на паузу, чтобы прошлый такт можно было рассмотреть
+func handleSessionKey(ev *termbox.Event) bool {
+	session := GetSession()
+	tick := GetProcessTable().tick
+	seeks := map[rune]int{',': -1, '[': -10, ']': 10, '{': -100, '}': 100}
+
+	switch {
+	case ev.Ch == 'p':
+		session.Paused = !session.Paused
+	case ev.Ch == '.' && session.Paused:
+		session.Step()
+	case ev.Ch == 's':
+		path := fmt.Sprintf("snapshot-%d.json", tick)
+		snapshotMessage = "Снимок сохранен в " + path
+		if err := SaveSnapshot(path); err != nil {
+			snapshotMessage = err.Error()
+		}
+	case seeks[ev.Ch] != 0:
+		if seeks[ev.Ch] < 0 {
+			session.Paused = true
+		}
+		session.Seek(tick + seeks[ev.Ch])
+	default:
+		return false
 	}
+
+	return true
+}
+
+// Выводит в строке состояния загрузку каждого процессора
+func drawCPUStatus() {
+	theme := uitools.CurrentTheme
+	for i, cpu := range GetProcessTable().cpus {
+		uitools.Printf(statusBarX+1+i*13, statusBarY, theme.Status.Fg, theme.Status.Bg, "CPU%d: %3d%%", cpu.ID, cpu.Utilization())
+	}
+}
+
+// stateKeys - ключи цветов темы для состояний процессов и потоков
+var stateKeys = map[ProcessState]string{
+	Execution:  "execution",
+	Readiness:  "readiness",
+	Blocking:   "blocking",
+	Terminated: "terminated",
+	Suspended:  "suspended",
+}
+
+// Возвращает цвета текущей темы для состояния процесса
+func stateStyle(state ProcessState) uitools.Style {
+	return uitools.CurrentTheme.State(stateKeys[state])
+}
+
+// Выбирает тему из конфигурации, применяет переопределенные цвета и делает ее текущей
+func applyTheme() error {
+	theme, err := uitools.ThemeByName(GetConfig().Theme)
+	if err != nil {
+		return err
+	}
+
+	for key, spec := range GetConfig().ThemeColors {
+		if err := theme.Set(key, spec); err != nil {
+			return err
+		}
+	}
+
+	uitools.SetTheme(theme)
+	return nil
 }
@@ -11,34 +11,72 @@ type Button struct {
 	fColor  termbox.Attribute
 	bColor  termbox.Attribute
 	action  func(*Button)
+	focused bool
 }
 
 // NewButton создает экземпляр структуры Button и возвращает указатель на новый экземпляр
 func NewButton(x, y int, caption string, fColor, bColor termbox.Attribute, action func(*Button)) *Button {
-	return &Button{x, y, caption, fColor, bColor, action}
+	return &Button{x: x, y: y, caption: caption, fColor: fColor, bColor: bColor, action: action}
 }
 
-// Draw отображает кнопку в позиции x, y
+// Draw отображает кнопку в позиции x, y, рамка кнопки в фокусе выделяется цветом
 func (b *Button) Draw() {
+	frameColor := b.fColor
+	if b.focused {
+		frameColor = CurrentTheme.Focused.Fg
+	}
+
 	length := len([]rune(b.caption))
 	for i := 0; i <= length+1; i++ {
-		termbox.SetCell(b.x+i, b.y, '▓', b.fColor, b.bColor)
-		termbox.SetCell(b.x+i, b.y+2, '▓', b.fColor, b.bColor)
+		termbox.SetCell(b.x+i, b.y, '▓', frameColor, b.bColor)
+		termbox.SetCell(b.x+i, b.y+2, '▓', frameColor, b.bColor)
 	}
 
-	termbox.SetCell(b.x, b.y+1, '▓', b.fColor, b.bColor)
-	termbox.SetCell(b.x+length+1, b.y+1, '▓', b.fColor, b.bColor)
+	termbox.SetCell(b.x, b.y+1, '▓', frameColor, b.bColor)
+	termbox.SetCell(b.x+length+1, b.y+1, '▓', frameColor, b.bColor)
 
 	Print(b.x+1, b.y+1, b.bColor, b.fColor, b.caption)
 }
 
+// HandleEvent выполняет действие кнопки по нажатию ЛКМ на ней или по Enter и пробелу в фокусе
+func (b *Button) HandleEvent(ev *termbox.Event) bool {
+	if b.focused && isActivation(ev) {
+		b.action(b)
+		return true
+	}
+
+	if ev.Type != termbox.EventMouse || ev.Key != termbox.MouseLeft {
+		return false
+	}
+
+	return b.CheckClick(ev.MouseX, ev.MouseY)
+}
+
+// SetFocus устанавливает или снимает фокус кнопки
+func (b *Button) SetFocus(focused bool) {
+	b.focused = focused
+}
+
+// Focused сообщает, находится ли кнопка в фокусе
+func (b *Button) Focused() bool {
+	return b.focused
+}
+
 // CheckClick проверяет пересечение переданных координат и отображаемого прямоугольника кнопки,
-// выполняет соответствующее кнопке действие
-func (b *Button) CheckClick(clickX, clickY int) {
+// выполняет соответствующее кнопке действие и сообщает о попадании
+func (b *Button) CheckClick(clickX, clickY int) bool {
 	length := len([]rune(b.caption))
 	if clickX >= b.x && clickX <= b.x+length+1 && clickY >= b.y && clickY <= b.y+2 {
 		b.action(b)
+		return true
 	}
+
+	return false
+}
+
+// Size возвращает ширину и высоту кнопки
+func (b *Button) Size() (int, int) {
+	return len([]rune(b.caption)) + 2, 3
 }
 
 // SetPosition меняет позицию кнопки на указанную
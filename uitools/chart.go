@@ -0,0 +1,127 @@
+package uitools
+
+import (
+	"fmt"
+
+	"github.com/nsf/termbox-go"
+)
+
+// brailleDots - биты точек символа Брайля по столбцам, снизу вверх
+var brailleDots = [2][4]rune{
+	{0x40, 0x04, 0x02, 0x01},
+	{0x80, 0x20, 0x10, 0x08},
+}
+
+// Chart представляет прокручиваемый график ряда значений, нарисованный точками Брайля
+// Каждый символ вмещает два значения по горизонтали и четыре уровня по вертикали
+type Chart struct {
+	x, y          int
+	width, height int
+	title         string
+	values        []float64
+	max           float64
+	fColor        termbox.Attribute
+	bColor        termbox.Attribute
+}
+
+// chartAxisWidth - ширина подписей шкалы слева от графика
+const chartAxisWidth = 8
+
+// NewChart создает экземпляр структуры Chart и возвращает указатель на новый экземпляр
+// При max == 0 шкала подбирается по наибольшему из отображаемых значений
+func NewChart(x, y, width, height int, title string, max float64, fColor, bColor termbox.Attribute) *Chart {
+	return &Chart{x: x, y: y, width: width, height: height, title: title, max: max, fColor: fColor, bColor: bColor}
+}
+
+// Draw отображает заголовок с последним значением и график последних значений, которые помещаются в его ширину
+func (c *Chart) Draw() {
+	rows := c.height - 1
+	columns := c.width - chartAxisWidth
+	if rows < 1 || columns < 1 {
+		return
+	}
+
+	values := c.values
+	if len(values) > columns*2 {
+		values = values[len(values)-columns*2:]
+	}
+
+	max := c.max
+	if max == 0 {
+		for _, v := range values {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	caption := c.title
+	if len(values) > 0 {
+		caption += fmt.Sprintf(": %.0f", values[len(values)-1])
+	}
+	Print(c.x, c.y, c.fColor, c.bColor, fitString(caption, c.width))
+
+	Printf(c.x, c.y+1, c.fColor, c.bColor, "%*.0f┤", chartAxisWidth-1, max)
+	Printf(c.x, c.y+rows, c.fColor, c.bColor, "%*d┤", chartAxisWidth-1, 0)
+
+	// Уровень каждого значения - число закрашенных точек снизу в столбце точек
+	levels := make([]int, len(values))
+	for i, v := range values {
+		level := int(v / max * float64(rows*4))
+		if level > rows*4 {
+			level = rows * 4
+		}
+		if v > 0 && level == 0 {
+			level = 1
+		}
+		levels[i] = level
+	}
+
+	for row := 0; row < rows; row++ {
+		// Нижняя строка графика содержит уровни 1-4, следующая 5-8 и так далее
+		bottom := (rows - 1 - row) * 4
+		for column := 0; column < columns; column++ {
+			symbol := rune(0x2800)
+			for half := 0; half < 2; half++ {
+				index := column*2 + half
+				if index >= len(levels) {
+					continue
+				}
+				for dot := 0; dot < 4; dot++ {
+					if levels[index] > bottom+dot {
+						symbol |= brailleDots[half][dot]
+					}
+				}
+			}
+			termbox.SetCell(c.x+chartAxisWidth+column, c.y+1+row, symbol, c.fColor, c.bColor)
+		}
+	}
+}
+
+// HandleEvent не обрабатывает события: график не интерактивен
+func (c *Chart) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// SetValues заменяет ряд значений графика
+func (c *Chart) SetValues(values []float64) {
+	c.values = values
+}
+
+// Size возвращает ширину и высоту графика вместе с заголовком
+func (c *Chart) Size() (int, int) {
+	return c.width, c.height
+}
+
+// SetSize меняет размер графика
+func (c *Chart) SetSize(width, height int) {
+	c.width, c.height = width, height
+}
+
+// SetPosition меняет позицию графика на указанную
+func (c *Chart) SetPosition(x, y int) {
+	c.x, c.y = x, y
+}
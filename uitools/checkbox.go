@@ -0,0 +1,94 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Checkbox представляет флажок с подписью
+type Checkbox struct {
+	x, y    int
+	caption string
+	checked bool
+	fColor  termbox.Attribute
+	bColor  termbox.Attribute
+	action  func(*Checkbox)
+	focused bool
+}
+
+// NewCheckbox создает экземпляр структуры Checkbox и возвращает указатель на новый экземпляр
+func NewCheckbox(x, y int, caption string, checked bool, fColor, bColor termbox.Attribute, action func(*Checkbox)) *Checkbox {
+	return &Checkbox{x: x, y: y, caption: caption, checked: checked, fColor: fColor, bColor: bColor, action: action}
+}
+
+// Draw отображает флажок в позиции x, y
+func (c *Checkbox) Draw() {
+	mark := ' '
+	if c.checked {
+		mark = 'x'
+	}
+
+	fColor := c.fColor
+	if c.focused {
+		fColor = CurrentTheme.Focused.Fg
+	}
+
+	Printf(c.x, c.y, fColor, c.bColor, "[%c] %s", mark, c.caption)
+}
+
+// HandleEvent переключает флажок по нажатию ЛКМ на нем или по Enter и пробелу в фокусе
+func (c *Checkbox) HandleEvent(ev *termbox.Event) bool {
+	if c.focused && isActivation(ev) {
+		c.Toggle()
+		return true
+	}
+
+	if ev.Type != termbox.EventMouse || ev.Key != termbox.MouseLeft {
+		return false
+	}
+
+	length := len([]rune(c.caption)) + 4
+	if ev.MouseX >= c.x && ev.MouseX < c.x+length && ev.MouseY == c.y {
+		c.Toggle()
+		return true
+	}
+
+	return false
+}
+
+// Toggle меняет состояние флажка и выполняет соответствующее ему действие
+func (c *Checkbox) Toggle() {
+	c.checked = !c.checked
+	if c.action != nil {
+		c.action(c)
+	}
+}
+
+// Checked возвращает состояние флажка
+func (c *Checkbox) Checked() bool {
+	return c.checked
+}
+
+// SetChecked устанавливает состояние флажка, не выполняя его действие
+func (c *Checkbox) SetChecked(checked bool) {
+	c.checked = checked
+}
+
+// Size возвращает ширину и высоту флажка с подписью
+func (c *Checkbox) Size() (int, int) {
+	return len([]rune(c.caption)) + 4, 1
+}
+
+// SetFocus устанавливает или снимает фокус флажка
+func (c *Checkbox) SetFocus(focused bool) {
+	c.focused = focused
+}
+
+// Focused сообщает, находится ли флажок в фокусе
+func (c *Checkbox) Focused() bool {
+	return c.focused
+}
+
+// SetPosition меняет позицию флажка на указанную
+func (c *Checkbox) SetPosition(x, y int) {
+	c.x, c.y = x, y
+}
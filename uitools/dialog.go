@@ -0,0 +1,98 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Dialog представляет модальное окно с рамкой, заголовком и вложенными элементами управления
+type Dialog struct {
+	x, y          int
+	width, height int
+	title         string
+	children      []Widget
+	visible       bool
+	fColor        termbox.Attribute
+	bColor        termbox.Attribute
+}
+
+// NewDialog создает скрытый экземпляр структуры Dialog и возвращает указатель на новый экземпляр
+// Координаты вложенных элементов задаются относительно экрана
+func NewDialog(x, y, width, height int, title string, fColor, bColor termbox.Attribute, children ...Widget) *Dialog {
+	return &Dialog{x, y, width, height, title, children, false, fColor, bColor}
+}
+
+// Draw отображает окно и вложенные элементы, если окно открыто
+func (d *Dialog) Draw() {
+	if !d.visible {
+		return
+	}
+
+	right, bottom := d.width-1, d.height-1
+	for j := 0; j <= bottom; j++ {
+		for i := 0; i <= right; i++ {
+			symbol := ' '
+			switch {
+			case i == 0 && j == 0:
+				symbol = '┌'
+			case i == right && j == 0:
+				symbol = '┐'
+			case i == 0 && j == bottom:
+				symbol = '└'
+			case i == right && j == bottom:
+				symbol = '┘'
+			case j == 0 || j == bottom:
+				symbol = '─'
+			case i == 0 || i == right:
+				symbol = '│'
+			}
+			termbox.SetCell(d.x+i, d.y+j, symbol, d.fColor, d.bColor)
+		}
+	}
+
+	Print(d.x+2, d.y, d.fColor, d.bColor, " "+d.title+" ")
+
+	for _, c := range d.children {
+		c.Draw()
+	}
+}
+
+// HandleEvent передает события вложенным элементам, пока окно открыто
+// Открытое окно поглощает все события, Escape закрывает окно
+func (d *Dialog) HandleEvent(ev *termbox.Event) bool {
+	if !d.visible {
+		return false
+	}
+
+	if ev.Type == termbox.EventKey && ev.Key == termbox.KeyEsc {
+		d.Close()
+		return true
+	}
+
+	for _, c := range d.children {
+		if c.HandleEvent(ev) {
+			break
+		}
+	}
+
+	return true
+}
+
+// Open показывает окно
+func (d *Dialog) Open() {
+	d.visible = true
+}
+
+// Close скрывает окно
+func (d *Dialog) Close() {
+	d.visible = false
+}
+
+// Visible сообщает, открыто ли окно
+func (d *Dialog) Visible() bool {
+	return d.visible
+}
+
+// Add добавляет вложенный элемент управления
+func (d *Dialog) Add(w Widget) {
+	d.children = append(d.children, w)
+}
@@ -0,0 +1,177 @@
+package uitools
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+// KeyBacktab - код клавиш Shift+Tab, которые termbox не распознает самостоятельно
+const KeyBacktab termbox.Key = 0xFF00
+
+// backtabSequence - последовательность, которую терминал передает при нажатии Shift+Tab
+var backtabSequence = []byte("\033[Z")
+
+// rawInput - необработанный ввод, оставшийся после разбора предыдущих событий
+var rawInput []byte
+
+// PollEvent ожидает событие так же, как termbox.PollEvent, но дополнительно распознает Shift+Tab
+func PollEvent() termbox.Event {
+	data := make([]byte, 64)
+
+	for {
+		if ev, ok := parseRawInput(); ok {
+			return ev
+		}
+
+		ev := termbox.PollRawEvent(data)
+		if ev.Type != termbox.EventRaw {
+			return ev
+		}
+		rawInput = append(rawInput, data[:ev.N]...)
+	}
+}
+
+// parseRawInput извлекает из необработанного ввода очередное событие
+// Возвращает false, если для разбора нужно дочитать ввод
+func parseRawInput() (termbox.Event, bool) {
+	for len(rawInput) > 0 {
+		if bytes.HasPrefix(rawInput, backtabSequence) {
+			rawInput = rawInput[len(backtabSequence):]
+			return termbox.Event{Type: termbox.EventKey, Key: KeyBacktab}, true
+		}
+
+		ev := termbox.ParseEvent(rawInput)
+		if ev.N > 0 {
+			rawInput = rawInput[ev.N:]
+		}
+		if ev.Type != termbox.EventNone {
+			return ev, true
+		}
+		if ev.N > 0 {
+			continue
+		}
+		// Полный, но не распознанный символ (например, недопустимый байт UTF-8) отбрасывается,
+		// иначе он навсегда останется в начале ввода
+		if utf8.FullRune(rawInput) {
+			rawInput = rawInput[1:]
+			continue
+		}
+		// Неполная последовательность без разобранных байтов - нужно дочитать ввод
+		break
+	}
+
+	return termbox.Event{}, false
+}
+
+// Focusable - элемент управления, способный принимать фокус клавиатуры
+type Focusable interface {
+	Widget
+	// SetFocus устанавливает или снимает фокус
+	SetFocus(focused bool)
+	// Focused сообщает, находится ли элемент в фокусе
+	Focused() bool
+}
+
+// FocusGroup передает фокус между элементами по Tab и Shift+Tab и направляет им события клавиатуры
+type FocusGroup struct {
+	widgets []Widget
+	focused int
+}
+
+// NewFocusGroup создает группу, в которой фокус получает первый способный принять его элемент
+func NewFocusGroup(widgets ...Widget) *FocusGroup {
+	g := &FocusGroup{widgets: widgets, focused: -1}
+	g.Next()
+
+	return g
+}
+
+// Next переводит фокус на следующий элемент группы
+func (g *FocusGroup) Next() {
+	g.move(1)
+}
+
+// Prev переводит фокус на предыдущий элемент группы
+func (g *FocusGroup) Prev() {
+	g.move(-1)
+}
+
+// move ищет ближайший в заданном направлении элемент, способный принять фокус
+func (g *FocusGroup) move(direction int) {
+	n := len(g.widgets)
+	for i := 1; i <= n; i++ {
+		index := ((g.focused+direction*i)%n + n) % n
+		if _, ok := g.widgets[index].(Focusable); ok {
+			g.setFocused(index)
+			return
+		}
+	}
+}
+
+// Focus переводит фокус на указанный элемент, если он входит в группу
+func (g *FocusGroup) Focus(w Widget) {
+	for i, v := range g.widgets {
+		if v == w {
+			if _, ok := v.(Focusable); ok {
+				g.setFocused(i)
+			}
+			return
+		}
+	}
+}
+
+// setFocused снимает фокус с текущего элемента и устанавливает его на элемент с заданным индексом
+func (g *FocusGroup) setFocused(index int) {
+	if g.focused != -1 {
+		g.widgets[g.focused].(Focusable).SetFocus(false)
+	}
+	g.focused = index
+	g.widgets[index].(Focusable).SetFocus(true)
+}
+
+// Draw отображает элементы группы
+func (g *FocusGroup) Draw() {
+	for _, w := range g.widgets {
+		w.Draw()
+	}
+}
+
+// HandleEvent переключает фокус по Tab и Shift+Tab, события клавиатуры передает элементу в фокусе
+// События мыши получают все элементы группы, элемент, поглотивший нажатие ЛКМ, получает фокус
+func (g *FocusGroup) HandleEvent(ev *termbox.Event) bool {
+	switch ev.Type {
+	case termbox.EventKey:
+		switch ev.Key {
+		case termbox.KeyTab:
+			g.Next()
+			return true
+		case KeyBacktab:
+			g.Prev()
+			return true
+		}
+
+		if g.focused != -1 {
+			return g.widgets[g.focused].HandleEvent(ev)
+		}
+	case termbox.EventMouse:
+		consumed := false
+		for _, w := range g.widgets {
+			if w.HandleEvent(ev) {
+				consumed = true
+				if ev.Key == termbox.MouseLeft {
+					g.Focus(w)
+				}
+			}
+		}
+		return consumed
+	}
+
+	return false
+}
+
+// isActivation проверяет, является ли событие нажатием Enter или пробела
+func isActivation(ev *termbox.Event) bool {
+	return ev.Type == termbox.EventKey && (ev.Key == termbox.KeyEnter || ev.Key == termbox.KeySpace)
+}
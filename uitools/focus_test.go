@@ -0,0 +1,100 @@
+package uitools
+
+import (
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+// click формирует событие нажатия ЛКМ в заданной позиции
+func click(x, y int) *termbox.Event {
+	return &termbox.Event{Type: termbox.EventMouse, Key: termbox.MouseLeft, MouseX: x, MouseY: y}
+}
+
+func TestFocusGroupClick(t *testing.T) {
+	first := NewTextInput(0, 0, 10, "первое", termbox.ColorDefault, termbox.ColorDefault, nil)
+	second := NewTextInput(0, 1, 10, "второе", termbox.ColorDefault, termbox.ColorDefault, nil)
+	g := NewFocusGroup(first, second)
+
+	steps := []struct {
+		name   string
+		ev     *termbox.Event
+		first  bool
+		second bool
+	}{
+		{"начальное состояние", nil, true, false},
+		{"нажатие на второе поле", click(3, 1), false, true},
+		{"нажатие вне полей", click(3, 5), false, true},
+		{"нажатие правее второго поля", click(10, 1), false, true},
+		{"нажатие на первое поле за концом текста", click(9, 0), true, false},
+	}
+
+	for _, step := range steps {
+		if step.ev != nil {
+			g.HandleEvent(step.ev)
+		}
+		if first.Focused() != step.first || second.Focused() != step.second {
+			t.Fatalf("%s: фокус первого поля %v, второго %v", step.name, first.Focused(), second.Focused())
+		}
+	}
+
+	// Ввод получает только поле в фокусе
+	g.HandleEvent(&termbox.Event{Type: termbox.EventKey, Ch: '!'})
+	if first.Text() != "первое!" || second.Text() != "второе" {
+		t.Fatalf("после ввода: %q, %q", first.Text(), second.Text())
+	}
+}
+
+func TestTextInputClickMovesCursor(t *testing.T) {
+	input := NewTextInput(5, 0, 10, "abc", termbox.ColorDefault, termbox.ColorDefault, nil)
+	input.SetFocus(true)
+
+	input.HandleEvent(click(6, 0))
+	input.HandleEvent(&termbox.Event{Type: termbox.EventKey, Ch: 'x'})
+	if input.Text() != "axbc" {
+		t.Fatalf("после нажатия на второй символ: %q", input.Text())
+	}
+
+	input.HandleEvent(click(14, 0))
+	input.HandleEvent(&termbox.Event{Type: termbox.EventKey, Ch: 'y'})
+	if input.Text() != "axbcy" {
+		t.Fatalf("после нажатия за концом текста: %q", input.Text())
+	}
+}
+
+func TestParseRawInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		ok     bool
+		key    termbox.Key
+		ch     rune
+		remain int
+	}{
+		{"символ", []byte("ab"), true, 0, 'a', 1},
+		{"Shift+Tab", []byte("\033[Zb"), true, KeyBacktab, 0, 1},
+		{"Tab", []byte("\t"), true, termbox.KeyTab, 0, 0},
+		{"недопустимый байт UTF-8", []byte{0xff, 'a'}, true, 0, 'a', 0},
+		{"только недопустимые байты", []byte{0xff, 0xfe}, false, 0, 0, 0},
+		{"неполный символ", []byte{0xd0}, false, 0, 0, 1},
+		{"пустой ввод", nil, false, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rawInput = append([]byte(nil), test.input...)
+			defer func() { rawInput = nil }()
+
+			ev, ok := parseRawInput()
+			if ok != test.ok {
+				t.Fatalf("событие извлечено: %v, ожидалось %v", ok, test.ok)
+			}
+			if ok && (ev.Type != termbox.EventKey || ev.Key != test.key || ev.Ch != test.ch) {
+				t.Fatalf("событие %+v, ожидалась клавиша %d, символ %q", ev, test.key, test.ch)
+			}
+			if len(rawInput) != test.remain {
+				t.Fatalf("осталось %d байт, ожидалось %d", len(rawInput), test.remain)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Label представляет надпись, отображаемую на экране
+type Label struct {
+	x, y   int
+	text   string
+	fColor termbox.Attribute
+	bColor termbox.Attribute
+}
+
+// NewLabel создает экземпляр структуры Label и возвращает указатель на новый экземпляр
+func NewLabel(x, y int, text string, fColor, bColor termbox.Attribute) *Label {
+	return &Label{x, y, text, fColor, bColor}
+}
+
+// Draw отображает надпись в позиции x, y
+func (l *Label) Draw() {
+	Print(l.x, l.y, l.fColor, l.bColor, l.text)
+}
+
+// HandleEvent не обрабатывает события: надпись не интерактивна
+func (l *Label) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// SetText меняет текст надписи
+func (l *Label) SetText(text string) {
+	l.text = text
+}
+
+// Size возвращает ширину и высоту надписи
+func (l *Label) Size() (int, int) {
+	return len([]rune(l.text)), 1
+}
+
+// SetPosition меняет позицию надписи на указанную
+func (l *Label) SetPosition(x, y int) {
+	l.x, l.y = x, y
+}
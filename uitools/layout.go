@@ -0,0 +1,269 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Layoutable - элемент управления, размещаемый компоновщиком
+type Layoutable interface {
+	Widget
+	// Size возвращает предпочтительные ширину и высоту элемента
+	Size() (int, int)
+	// SetPosition меняет позицию элемента
+	SetPosition(x, y int)
+}
+
+// Resizable - элемент управления, размер которого может задавать компоновщик
+type Resizable interface {
+	Layoutable
+	// SetSize меняет размер элемента
+	SetSize(width, height int)
+}
+
+// LayoutKind перечисляет способы размещения элементов в контейнере
+type LayoutKind int
+
+const (
+	// Vertical размещает элементы сверху вниз
+	Vertical LayoutKind = iota
+	// Horizontal размещает элементы слева направо
+	Horizontal
+	// Grid размещает элементы по строкам сетки с заданным числом столбцов
+	Grid
+)
+
+// layoutItem - элемент контейнера и признак растяжения на свободное место
+type layoutItem struct {
+	widget  Layoutable
+	stretch bool
+}
+
+// Layout представляет контейнер, пересчитывающий позиции вложенных элементов при изменении размера
+type Layout struct {
+	x, y          int
+	width, height int
+	kind          LayoutKind
+	columns       int
+	spacing       int
+	items         []layoutItem
+	// focus - группа фокуса, получающая события вместо вложенных элементов, nil - события получают все элементы
+	focus *FocusGroup
+}
+
+// NewVerticalLayout создает контейнер, размещающий элементы сверху вниз
+func NewVerticalLayout(spacing int) *Layout {
+	return &Layout{kind: Vertical, spacing: spacing}
+}
+
+// NewHorizontalLayout создает контейнер, размещающий элементы слева направо
+func NewHorizontalLayout(spacing int) *Layout {
+	return &Layout{kind: Horizontal, spacing: spacing}
+}
+
+// NewGridLayout создает контейнер, размещающий элементы в сетке с заданным числом столбцов
+func NewGridLayout(columns, spacing int) *Layout {
+	return &Layout{kind: Grid, columns: columns, spacing: spacing}
+}
+
+// Add добавляет элемент с предпочтительным размером и возвращает контейнер
+func (l *Layout) Add(w Layoutable) *Layout {
+	l.items = append(l.items, layoutItem{w, false})
+	return l
+}
+
+// AddStretch добавляет элемент, занимающий свободное место вдоль оси контейнера, и возвращает контейнер
+func (l *Layout) AddStretch(w Layoutable) *Layout {
+	l.items = append(l.items, layoutItem{w, true})
+	return l
+}
+
+// WithFocus направляет события контейнера группе фокуса и возвращает контейнер
+// Элементы отображает контейнер, а события клавиатуры получает только элемент в фокусе
+func (l *Layout) WithFocus(g *FocusGroup) *Layout {
+	l.focus = g
+	return l
+}
+
+// AddSpacer добавляет пустое растягиваемое место и возвращает контейнер
+func (l *Layout) AddSpacer() *Layout {
+	return l.AddStretch(&spacer{})
+}
+
+// Arrange размещает контейнер в заданном прямоугольнике и пересчитывает позиции вложенных элементов
+func (l *Layout) Arrange(x, y, width, height int) {
+	l.x, l.y, l.width, l.height = x, y, width, height
+
+	switch l.kind {
+	case Vertical, Horizontal:
+		l.arrangeLinear()
+	case Grid:
+		l.arrangeGrid()
+	}
+}
+
+// arrangeLinear размещает элементы вдоль одной оси, деля свободное место между растягиваемыми элементами
+func (l *Layout) arrangeLinear() {
+	length, cross := l.height, l.width
+	if l.kind == Horizontal {
+		length, cross = l.width, l.height
+	}
+
+	free := length - l.spacing*(len(l.items)-1)
+	stretchCount := 0
+	for _, item := range l.items {
+		if item.stretch {
+			stretchCount++
+			continue
+		}
+		free -= l.along(item.widget)
+	}
+
+	pos := 0
+	for _, item := range l.items {
+		size := l.along(item.widget)
+		if item.stretch {
+			size = 0
+			if free > 0 {
+				size = free / stretchCount
+			}
+		}
+
+		if l.kind == Vertical {
+			item.widget.SetPosition(l.x, l.y+pos)
+			if r, ok := item.widget.(Resizable); ok && item.stretch {
+				r.SetSize(cross, size)
+			}
+		} else {
+			item.widget.SetPosition(l.x+pos, l.y)
+			if r, ok := item.widget.(Resizable); ok && item.stretch {
+				r.SetSize(size, cross)
+			}
+		}
+
+		pos += size + l.spacing
+	}
+}
+
+// arrangeGrid размещает элементы по ячейкам сетки равной ширины
+func (l *Layout) arrangeGrid() {
+	if l.columns <= 0 {
+		return
+	}
+
+	cellWidth := (l.width - l.spacing*(l.columns-1)) / l.columns
+	top := l.y
+	for row := 0; row*l.columns < len(l.items); row++ {
+		rowHeight := 0
+		for col := 0; col < l.columns && row*l.columns+col < len(l.items); col++ {
+			if _, h := l.items[row*l.columns+col].widget.Size(); h > rowHeight {
+				rowHeight = h
+			}
+		}
+
+		for col := 0; col < l.columns && row*l.columns+col < len(l.items); col++ {
+			item := l.items[row*l.columns+col]
+			item.widget.SetPosition(l.x+col*(cellWidth+l.spacing), top)
+			if r, ok := item.widget.(Resizable); ok && item.stretch {
+				r.SetSize(cellWidth, rowHeight)
+			}
+		}
+
+		top += rowHeight + l.spacing
+	}
+}
+
+// along возвращает предпочтительный размер элемента вдоль оси контейнера
+func (l *Layout) along(w Layoutable) int {
+	width, height := w.Size()
+	if l.kind == Horizontal {
+		return width
+	}
+
+	return height
+}
+
+// Draw отображает вложенные элементы
+func (l *Layout) Draw() {
+	for _, item := range l.items {
+		item.widget.Draw()
+	}
+}
+
+// HandleEvent передает событие группе фокуса, если она задана, иначе вложенным элементам до первого поглотившего его
+func (l *Layout) HandleEvent(ev *termbox.Event) bool {
+	if l.focus != nil {
+		return l.focus.HandleEvent(ev)
+	}
+
+	for _, item := range l.items {
+		if item.widget.HandleEvent(ev) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Size возвращает предпочтительный размер контейнера по размерам вложенных элементов
+func (l *Layout) Size() (int, int) {
+	width, height := 0, 0
+	for i, item := range l.items {
+		w, h := item.widget.Size()
+		switch l.kind {
+		case Vertical:
+			if w > width {
+				width = w
+			}
+			height += h
+			if i > 0 {
+				height += l.spacing
+			}
+		case Horizontal:
+			if h > height {
+				height = h
+			}
+			width += w
+			if i > 0 {
+				width += l.spacing
+			}
+		case Grid:
+			if w > width {
+				width = w
+			}
+			if h > height {
+				height = h
+			}
+		}
+	}
+
+	if l.kind == Grid && l.columns > 0 {
+		rows := (len(l.items) + l.columns - 1) / l.columns
+		width = width*l.columns + l.spacing*(l.columns-1)
+		height = height*rows + l.spacing*(rows-1)
+	}
+
+	return width, height
+}
+
+// SetPosition переносит контейнер, сохраняя его размер
+// Контейнер, размер которого еще не задан, получает предпочтительный размер
+func (l *Layout) SetPosition(x, y int) {
+	width, height := l.width, l.height
+	if width == 0 && height == 0 {
+		width, height = l.Size()
+	}
+	l.Arrange(x, y, width, height)
+}
+
+// SetSize меняет размер контейнера, сохраняя его позицию
+func (l *Layout) SetSize(width, height int) {
+	l.Arrange(l.x, l.y, width, height)
+}
+
+// spacer - пустой элемент, занимающий свободное место в контейнере
+type spacer struct{}
+
+func (s *spacer) Draw()                              {}
+func (s *spacer) HandleEvent(ev *termbox.Event) bool { return false }
+func (s *spacer) Size() (int, int)                   { return 0, 0 }
+func (s *spacer) SetPosition(x, y int)               {}
@@ -0,0 +1,147 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// ListBox представляет прокручиваемый список строк с выбором элемента
+type ListBox struct {
+	x, y          int
+	width, height int
+	items         []string
+	first         int
+	selected      int
+	fColor        termbox.Attribute
+	bColor        termbox.Attribute
+	action        func(*ListBox)
+	focused       bool
+}
+
+// NewListBox создает экземпляр структуры ListBox и возвращает указатель на новый экземпляр
+// Действие выполняется при выборе элемента
+func NewListBox(x, y, width, height int, items []string, fColor, bColor termbox.Attribute, action func(*ListBox)) *ListBox {
+	return &ListBox{x: x, y: y, width: width, height: height, items: items, selected: -1, fColor: fColor, bColor: bColor, action: action}
+}
+
+// Draw отображает видимую часть списка, выделяя выбранный элемент
+func (l *ListBox) Draw() {
+	for i := 0; i < l.height; i++ {
+		fColor, bColor := l.fColor, l.bColor
+		if i+l.first == l.selected {
+			fColor, bColor = CurrentTheme.Selected.Fg, CurrentTheme.Selected.Bg
+			if l.focused {
+				bColor = CurrentTheme.Focused.Fg
+			}
+		}
+
+		text := ""
+		if i+l.first < len(l.items) {
+			text = l.items[i+l.first]
+		}
+		Print(l.x, l.y+i, fColor, bColor, fitString(text, l.width))
+	}
+}
+
+// HandleEvent выбирает элемент по нажатию ЛКМ или стрелками в фокусе и прокручивает список колесом мыши
+func (l *ListBox) HandleEvent(ev *termbox.Event) bool {
+	if ev.Type == termbox.EventKey && l.focused {
+		switch ev.Key {
+		case termbox.KeyArrowUp:
+			l.moveSelection(-1)
+			return true
+		case termbox.KeyArrowDown:
+			l.moveSelection(1)
+			return true
+		}
+		return false
+	}
+
+	if ev.Type != termbox.EventMouse {
+		return false
+	}
+
+	if ev.MouseX < l.x || ev.MouseX >= l.x+l.width || ev.MouseY < l.y || ev.MouseY >= l.y+l.height {
+		return false
+	}
+
+	switch ev.Key {
+	case termbox.MouseLeft:
+		if index := ev.MouseY - l.y + l.first; index < len(l.items) {
+			l.Select(index)
+		}
+	case termbox.MouseWheelUp:
+		l.Scroll(-1)
+	case termbox.MouseWheelDown:
+		l.Scroll(1)
+	}
+
+	return true
+}
+
+// Scroll сдвигает видимую часть списка на delta строк
+func (l *ListBox) Scroll(delta int) {
+	l.first = clamp(l.first+delta, 0, len(l.items)-l.height)
+}
+
+// Select выбирает элемент с заданным индексом и выполняет действие списка
+func (l *ListBox) Select(index int) {
+	l.selected = index
+	if l.action != nil {
+		l.action(l)
+	}
+}
+
+// moveSelection сдвигает выбор на delta элементов и прокручивает список к выбранному
+func (l *ListBox) moveSelection(delta int) {
+	if len(l.items) == 0 {
+		return
+	}
+
+	l.Select(clamp(l.selected+delta, 0, len(l.items)-1))
+	if l.selected < l.first {
+		l.first = l.selected
+	}
+	if l.selected >= l.first+l.height {
+		l.first = l.selected - l.height + 1
+	}
+}
+
+// SetFocus устанавливает или снимает фокус списка
+func (l *ListBox) SetFocus(focused bool) {
+	l.focused = focused
+}
+
+// Focused сообщает, находится ли список в фокусе
+func (l *ListBox) Focused() bool {
+	return l.focused
+}
+
+// Selected возвращает индекс выбранного элемента или -1
+func (l *ListBox) Selected() int {
+	return l.selected
+}
+
+// SetItems заменяет элементы списка
+func (l *ListBox) SetItems(items []string) {
+	l.items = items
+	if l.selected >= len(items) {
+		l.selected = -1
+	}
+	l.Scroll(0)
+}
+
+// Size возвращает ширину и высоту списка
+func (l *ListBox) Size() (int, int) {
+	return l.width, l.height
+}
+
+// SetSize меняет размер видимой части списка
+func (l *ListBox) SetSize(width, height int) {
+	l.width, l.height = width, height
+	l.Scroll(0)
+}
+
+// SetPosition меняет позицию списка на указанную
+func (l *ListBox) SetPosition(x, y int) {
+	l.x, l.y = x, y
+}
@@ -0,0 +1,71 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// ProgressBar представляет индикатор заполнения
+type ProgressBar struct {
+	x, y, width int
+	value, max  int
+	fColor      termbox.Attribute
+	bColor      termbox.Attribute
+}
+
+// NewProgressBar создает экземпляр структуры ProgressBar и возвращает указатель на новый экземпляр
+func NewProgressBar(x, y, width, max int, fColor, bColor termbox.Attribute) *ProgressBar {
+	return &ProgressBar{x: x, y: y, width: width, max: max, fColor: fColor, bColor: bColor}
+}
+
+// Draw отображает индикатор и процент заполнения в позиции x, y
+func (p *ProgressBar) Draw() {
+	filled := 0
+	percent := 0
+	if p.max > 0 {
+		filled = p.value * p.width / p.max
+		percent = p.value * 100 / p.max
+	}
+
+	for i := 0; i < p.width; i++ {
+		symbol := '░'
+		if i < filled {
+			symbol = '▓'
+		}
+		termbox.SetCell(p.x+i, p.y, symbol, p.fColor, p.bColor)
+	}
+
+	Printf(p.x+p.width+1, p.y, p.fColor, p.bColor, "%3d%%", percent)
+}
+
+// HandleEvent не обрабатывает события: индикатор не интерактивен
+func (p *ProgressBar) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// SetValue меняет текущее значение индикатора, ограничивая его максимумом
+func (p *ProgressBar) SetValue(value int) {
+	if value > p.max {
+		value = p.max
+	}
+	if value < 0 {
+		value = 0
+	}
+	p.value = value
+}
+
+// Size возвращает ширину и высоту индикатора вместе с процентами
+func (p *ProgressBar) Size() (int, int) {
+	return p.width + 5, 1
+}
+
+// SetSize меняет ширину индикатора, оставляя место для процентов
+func (p *ProgressBar) SetSize(width, height int) {
+	if width > 5 {
+		p.width = width - 5
+	}
+}
+
+// SetPosition меняет позицию индикатора на указанную
+func (p *ProgressBar) SetPosition(x, y int) {
+	p.x, p.y = x, y
+}
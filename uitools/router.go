@@ -0,0 +1,107 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// EventKind перечисляет виды событий, на которые подписываются обработчики
+type EventKind int
+
+const (
+	// MouseEvent - нажатие кнопки, колесо или перемещение мыши
+	MouseEvent EventKind = iota
+	// KeyEvent - нажатие клавиши
+	KeyEvent
+	// ResizeEvent - изменение размера терминала
+	ResizeEvent
+	// TickEvent - такт модели, порождается прерыванием ожидания событий
+	TickEvent
+)
+
+// Handler обрабатывает событие и сообщает, поглощено ли оно
+type Handler func(ev *termbox.Event) bool
+
+// EventNode - узел дерева обработчиков событий: приложение, экран или элемент управления
+// Событие сначала получают вложенные узлы, непоглощенное событие всплывает к родителю
+type EventNode struct {
+	children []*EventNode
+	handlers map[EventKind][]Handler
+	active   func() bool
+}
+
+// NewEventNode создает корневой узел дерева обработчиков
+func NewEventNode() *EventNode {
+	return &EventNode{handlers: map[EventKind][]Handler{}}
+}
+
+// Child создает вложенный узел
+func (n *EventNode) Child() *EventNode {
+	child := NewEventNode()
+	n.children = append(n.children, child)
+
+	return child
+}
+
+// On подписывает обработчик на события заданного вида и возвращает узел
+func (n *EventNode) On(kind EventKind, handler Handler) *EventNode {
+	n.handlers[kind] = append(n.handlers[kind], handler)
+	return n
+}
+
+// AddWidget создает вложенный узел, передающий элементу управления события мыши и клавиатуры
+func (n *EventNode) AddWidget(w Widget) *EventNode {
+	return n.Child().On(MouseEvent, w.HandleEvent).On(KeyEvent, w.HandleEvent)
+}
+
+// SetActive задает условие, при котором узел и его вложенные узлы получают события, и возвращает узел
+func (n *EventNode) SetActive(active func() bool) *EventNode {
+	n.active = active
+	return n
+}
+
+// Dispatch передает событие активным узлам дерева и сообщает, было ли оно поглощено
+func (n *EventNode) Dispatch(ev *termbox.Event) bool {
+	kind, ok := EventKindOf(ev)
+	if !ok {
+		return false
+	}
+
+	return n.dispatch(kind, ev)
+}
+
+// dispatch передает событие вложенным узлам, а затем, если его никто не поглотил, обработчикам узла
+func (n *EventNode) dispatch(kind EventKind, ev *termbox.Event) bool {
+	if n.active != nil && !n.active() {
+		return false
+	}
+
+	for _, child := range n.children {
+		if child.dispatch(kind, ev) {
+			return true
+		}
+	}
+
+	for _, handler := range n.handlers[kind] {
+		if handler(ev) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EventKindOf определяет вид события termbox, события без вида не передаются обработчикам
+func EventKindOf(ev *termbox.Event) (EventKind, bool) {
+	switch ev.Type {
+	case termbox.EventMouse:
+		return MouseEvent, true
+	case termbox.EventKey:
+		return KeyEvent, true
+	case termbox.EventResize:
+		return ResizeEvent, true
+	case termbox.EventInterrupt:
+		return TickEvent, true
+	}
+
+	return 0, false
+}
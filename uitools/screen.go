@@ -0,0 +1,114 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// Screen - независимый экран приложения
+type Screen interface {
+	Widget
+	// Title возвращает название экрана для строки меню
+	Title() string
+	// Enter вызывается при переходе на экран
+	Enter()
+	// Leave вызывается при уходе с экрана
+	Leave()
+}
+
+// screenEntry - экран и функциональная клавиша, по которой он открывается
+type screenEntry struct {
+	key    termbox.Key
+	label  string
+	screen Screen
+}
+
+// ScreenManager переключает экраны по функциональным клавишам и отображает строку меню в первой строке экрана
+type ScreenManager struct {
+	entries []screenEntry
+	current int
+}
+
+// NewScreenManager создает экземпляр структуры ScreenManager без экранов
+func NewScreenManager() *ScreenManager {
+	return &ScreenManager{current: -1}
+}
+
+// Add регистрирует экран, открываемый клавишей key с подписью label, первый экран открывается сразу
+func (m *ScreenManager) Add(key termbox.Key, label string, screen Screen) {
+	m.entries = append(m.entries, screenEntry{key, label, screen})
+	if m.current == -1 {
+		m.Switch(0)
+	}
+}
+
+// Switch покидает текущий экран и открывает экран с заданным индексом
+func (m *ScreenManager) Switch(index int) {
+	if index == m.current || index < 0 || index >= len(m.entries) {
+		return
+	}
+
+	if m.current != -1 {
+		m.entries[m.current].screen.Leave()
+	}
+	m.current = index
+	m.entries[m.current].screen.Enter()
+}
+
+// Current возвращает открытый экран или nil
+func (m *ScreenManager) Current() Screen {
+	if m.current == -1 {
+		return nil
+	}
+
+	return m.entries[m.current].screen
+}
+
+// Draw отображает строку меню и открытый экран
+func (m *ScreenManager) Draw() {
+	width, _ := termbox.Size()
+	for i := 0; i < width; i++ {
+		termbox.SetCell(i, 0, ' ', CurrentTheme.Status.Fg, CurrentTheme.Status.Bg)
+	}
+
+	x := 0
+	for i, e := range m.entries {
+		style := CurrentTheme.Status
+		if i == m.current {
+			style = CurrentTheme.Selected
+		}
+
+		caption := " " + e.label + " " + e.screen.Title() + " "
+		Print(x, 0, style.Fg, style.Bg, caption)
+		x += len([]rune(caption)) + 1
+	}
+
+	if screen := m.Current(); screen != nil {
+		screen.Draw()
+	}
+}
+
+// HandleEvent передает событие открытому экрану, непоглощенные функциональные клавиши переключают экраны
+// Изменение размера терминала передается всем экранам
+func (m *ScreenManager) HandleEvent(ev *termbox.Event) bool {
+	if ev.Type == termbox.EventResize {
+		for _, e := range m.entries {
+			e.screen.HandleEvent(ev)
+		}
+		return false
+	}
+
+	if screen := m.Current(); screen != nil && screen.HandleEvent(ev) {
+		return true
+	}
+
+	if ev.Type == termbox.EventKey {
+		for i, e := range m.entries {
+			if e.key == ev.Key {
+				m.Switch(i)
+				return true
+			}
+		}
+	}
+
+	return false
+}
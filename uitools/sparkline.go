@@ -0,0 +1,74 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// sparkSymbols - символы уровней мини-графика от наименьшего к наибольшему
+var sparkSymbols = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline представляет однострочный мини-график последних значений ряда
+type Sparkline struct {
+	x, y, width int
+	values      []float64
+	fColor      termbox.Attribute
+	bColor      termbox.Attribute
+}
+
+// NewSparkline создает экземпляр структуры Sparkline и возвращает указатель на новый экземпляр
+func NewSparkline(x, y, width int, fColor, bColor termbox.Attribute) *Sparkline {
+	return &Sparkline{x: x, y: y, width: width, fColor: fColor, bColor: bColor}
+}
+
+// Draw отображает последние значения ряда, которые помещаются в ширину графика
+// Высота столбца пропорциональна значению относительно наибольшего из отображаемых
+func (s *Sparkline) Draw() {
+	values := s.values
+	if len(values) > s.width {
+		values = values[len(values)-s.width:]
+	}
+
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	for i := 0; i < s.width; i++ {
+		symbol := ' '
+		if i < len(values) {
+			level := 0
+			if max > 0 && values[i] > 0 {
+				level = int(values[i] / max * float64(len(sparkSymbols)-1))
+			}
+			symbol = sparkSymbols[level]
+		}
+		termbox.SetCell(s.x+i, s.y, symbol, s.fColor, s.bColor)
+	}
+}
+
+// HandleEvent не обрабатывает события: график не интерактивен
+func (s *Sparkline) HandleEvent(ev *termbox.Event) bool {
+	return false
+}
+
+// SetValues заменяет ряд значений графика
+func (s *Sparkline) SetValues(values []float64) {
+	s.values = values
+}
+
+// Size возвращает ширину и высоту графика
+func (s *Sparkline) Size() (int, int) {
+	return s.width, 1
+}
+
+// SetSize меняет ширину графика
+func (s *Sparkline) SetSize(width, height int) {
+	s.width = width
+}
+
+// SetPosition меняет позицию графика на указанную
+func (s *Sparkline) SetPosition(x, y int) {
+	s.x, s.y = x, y
+}
@@ -0,0 +1,307 @@
+package uitools
+
+import (
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Column описывает столбец таблицы
+type Column struct {
+	Title string
+	Width int
+}
+
+// Table представляет прокручиваемую таблицу с выбором строки
+type Table struct {
+	x, y        int
+	columns     []Column
+	rows        [][]string
+	visibleRows int
+	first       int
+	selected    int
+	fColor      termbox.Attribute
+	bColor      termbox.Attribute
+	action      func(*Table)
+	focused     bool
+	rowStyle    func(int) Style
+	// sortColumn - столбец, по которому упорядочены строки, -1 если порядок не задан
+	sortColumn     int
+	sortDescending bool
+	sortAction     func(*Table)
+}
+
+// NewTable создает экземпляр структуры Table и возвращает указатель на новый экземпляр
+// Действие выполняется при выборе строки
+func NewTable(x, y int, columns []Column, visibleRows int, fColor, bColor termbox.Attribute, action func(*Table)) *Table {
+	return &Table{x: x, y: y, columns: columns, visibleRows: visibleRows, selected: -1, sortColumn: -1, fColor: fColor, bColor: bColor, action: action}
+}
+
+// border формирует горизонтальную линию таблицы из заданных угловых и соединительных символов
+func (t *Table) border(left, middle, right string) string {
+	parts := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		parts[i] = strings.Repeat("─", c.Width)
+	}
+
+	return left + strings.Join(parts, middle) + right
+}
+
+// line формирует строку таблицы из значений ячеек, заголовки выравниваются влево, значения - вправо
+func (t *Table) line(cells []string, isHeader bool) string {
+	var sb strings.Builder
+	sb.WriteString("│")
+	for i, c := range t.columns {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if isHeader {
+			sb.WriteString(fitString(cell, c.Width))
+		} else {
+			sb.WriteString(alignRight(cell, c.Width))
+		}
+		sb.WriteString("│")
+	}
+
+	return sb.String()
+}
+
+// Width возвращает ширину таблицы в символах
+func (t *Table) Width() int {
+	width := 1
+	for _, c := range t.columns {
+		width += c.Width + 1
+	}
+
+	return width
+}
+
+// Draw отображает видимую часть таблицы в позиции x, y, выделяя выбранную строку
+// Рамка таблицы в фокусе выделяется цветом
+func (t *Table) Draw() {
+	frameColor := t.fColor
+	if t.focused {
+		frameColor = CurrentTheme.Focused.Fg
+	}
+
+	// Заголовок столбца сортировки отмечается направлением
+	titles := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		titles[i] = c.Title
+		if i == t.sortColumn {
+			mark := "▲"
+			if t.sortDescending {
+				mark = "▼"
+			}
+			titles[i] = fitString(c.Title, c.Width-1) + mark
+		}
+	}
+
+	Print(t.x, t.y, frameColor, t.bColor, t.border("┌", "┬", "┐"))
+	Print(t.x, t.y+1, t.fColor, t.bColor, t.line(titles, true))
+	Print(t.x, t.y+2, frameColor, t.bColor, t.border("├", "┼", "┤"))
+
+	shown := 0
+	for i := 0; i < t.visibleRows && i+t.first < len(t.rows); i++ {
+		fColor, bColor := t.fColor, t.bColor
+		if t.rowStyle != nil {
+			style := t.rowStyle(i + t.first)
+			fColor, bColor = style.Fg, style.Bg
+		}
+		if i+t.first == t.selected {
+			fColor, bColor = CurrentTheme.Selected.Fg, CurrentTheme.Selected.Bg
+		}
+		Print(t.x, t.y+3+i*2, fColor, bColor, t.line(t.rows[i+t.first], false))
+		Print(t.x, t.y+4+i*2, frameColor, t.bColor, t.border("├", "┼", "┤"))
+		shown++
+	}
+
+	Print(t.x, t.y+2+shown*2, frameColor, t.bColor, t.border("└", "┴", "┘"))
+}
+
+// HandleEvent выбирает строку по нажатию ЛКМ, прокручивает таблицу колесом мыши
+// Нажатие на заголовок столбца упорядочивает по нему строки, повторное - меняет направление
+// Стрелки в фокусе выбирают строку, вне фокуса - прокручивают таблицу
+// Нажатие вне таблицы снимает выделение
+func (t *Table) HandleEvent(ev *termbox.Event) bool {
+	switch ev.Type {
+	case termbox.EventMouse:
+		inside := ev.MouseX >= t.x && ev.MouseX < t.x+t.Width() && ev.MouseY >= t.y && ev.MouseY <= t.y+2+t.visibleRows*2
+
+		switch ev.Key {
+		case termbox.MouseLeft:
+			if !inside {
+				t.selected = -1
+				return false
+			}
+			if ev.MouseY == t.y+1 {
+				if column := t.columnAt(ev.MouseX); column != -1 && t.sortAction != nil {
+					t.SetSort(column, column == t.sortColumn && !t.sortDescending)
+					t.sortAction(t)
+				}
+				return true
+			}
+			if row := ev.MouseY - t.y - 3; row >= 0 && row%2 == 0 && row/2+t.first < len(t.rows) {
+				t.Select(row/2 + t.first)
+			}
+			return true
+		case termbox.MouseWheelUp:
+			t.Scroll(-1)
+			return true
+		case termbox.MouseWheelDown:
+			t.Scroll(1)
+			return true
+		}
+	case termbox.EventKey:
+		delta := 0
+		switch ev.Key {
+		case termbox.KeyArrowUp:
+			delta = -1
+		case termbox.KeyArrowDown:
+			delta = 1
+		case termbox.KeyPgup:
+			delta = -t.visibleRows
+		case termbox.KeyPgdn:
+			delta = t.visibleRows
+		default:
+			return false
+		}
+
+		if t.focused {
+			t.moveSelection(delta)
+		} else {
+			t.Scroll(delta)
+		}
+		return true
+	}
+
+	return false
+}
+
+// columnAt возвращает индекс столбца, содержащего экранную координату x, или -1
+func (t *Table) columnAt(x int) int {
+	left := t.x + 1
+	for i, c := range t.columns {
+		if x >= left && x < left+c.Width {
+			return i
+		}
+		left += c.Width + 1
+	}
+
+	return -1
+}
+
+// Scroll сдвигает видимую часть таблицы на delta строк
+func (t *Table) Scroll(delta int) {
+	t.first = clamp(t.first+delta, 0, len(t.rows)-t.visibleRows)
+}
+
+// Select выбирает строку с заданным индексом и выполняет действие таблицы
+func (t *Table) Select(index int) {
+	t.selected = index
+	if t.action != nil {
+		t.action(t)
+	}
+}
+
+// moveSelection сдвигает выбор на delta строк и прокручивает таблицу к выбранной строке
+func (t *Table) moveSelection(delta int) {
+	if len(t.rows) == 0 {
+		return
+	}
+
+	t.Select(clamp(t.selected+delta, 0, len(t.rows)-1))
+	if t.selected < t.first {
+		t.first = t.selected
+	}
+	if t.selected >= t.first+t.visibleRows {
+		t.first = t.selected - t.visibleRows + 1
+	}
+}
+
+// SetFocus устанавливает или снимает фокус таблицы
+func (t *Table) SetFocus(focused bool) {
+	t.focused = focused
+}
+
+// Focused сообщает, находится ли таблица в фокусе
+func (t *Table) Focused() bool {
+	return t.focused
+}
+
+// Selected возвращает индекс выбранной строки или -1
+func (t *Table) Selected() int {
+	return t.selected
+}
+
+// First возвращает индекс первой видимой строки
+func (t *Table) First() int {
+	return t.first
+}
+
+// RowY возвращает экранную координату y строки с заданным индексом или -1, если строка не видна
+func (t *Table) RowY(index int) int {
+	if index < t.first || index >= t.first+t.visibleRows {
+		return -1
+	}
+
+	return t.y + 3 + (index-t.first)*2
+}
+
+// SetRowStyle задает функцию, выбирающую цвета строки по ее индексу
+func (t *Table) SetRowStyle(rowStyle func(int) Style) {
+	t.rowStyle = rowStyle
+}
+
+// SetSortAction задает действие, выполняемое при смене порядка строк нажатием на заголовок
+// Таблица только отмечает столбец сортировки, упорядочивает строки владелец таблицы
+func (t *Table) SetSortAction(sortAction func(*Table)) {
+	t.sortAction = sortAction
+}
+
+// SetSort задает столбец и направление сортировки
+func (t *Table) SetSort(column int, descending bool) {
+	t.sortColumn, t.sortDescending = column, descending
+}
+
+// Sort возвращает столбец сортировки или -1 и направление сортировки
+func (t *Table) Sort() (int, bool) {
+	return t.sortColumn, t.sortDescending
+}
+
+// SetSelected выбирает строку с заданным индексом или снимает выбор при -1, не выполняя действие таблицы
+func (t *Table) SetSelected(index int) {
+	if index < -1 || index >= len(t.rows) {
+		index = -1
+	}
+	t.selected = index
+}
+
+// SetRows заменяет содержимое таблицы
+func (t *Table) SetRows(rows [][]string) {
+	t.rows = rows
+	if t.selected >= len(rows) {
+		t.selected = -1
+	}
+	t.Scroll(0)
+}
+
+// Size возвращает ширину и высоту таблицы при заданном числе видимых строк
+func (t *Table) Size() (int, int) {
+	return t.Width(), 3 + t.visibleRows*2
+}
+
+// SetSize меняет число видимых строк по доступной высоте, ширина определяется столбцами
+func (t *Table) SetSize(width, height int) {
+	t.visibleRows = 0
+	if height > 3 {
+		t.visibleRows = (height - 3) / 2
+	}
+	t.Scroll(0)
+}
+
+// SetPosition меняет позицию таблицы на указанную
+func (t *Table) SetPosition(x, y int) {
+	t.x, t.y = x, y
+}
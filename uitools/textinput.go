@@ -0,0 +1,145 @@
+package uitools
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// TextInput представляет однострочное поле ввода
+type TextInput struct {
+	x, y, width int
+	text        []rune
+	cursor      int
+	focused     bool
+	fColor      termbox.Attribute
+	bColor      termbox.Attribute
+	action      func(*TextInput)
+}
+
+// NewTextInput создает экземпляр структуры TextInput и возвращает указатель на новый экземпляр
+// Действие выполняется по нажатию Enter
+func NewTextInput(x, y, width int, text string, fColor, bColor termbox.Attribute, action func(*TextInput)) *TextInput {
+	runes := []rune(text)
+	return &TextInput{x: x, y: y, width: width, text: runes, cursor: len(runes), fColor: fColor, bColor: bColor, action: action}
+}
+
+// Draw отображает поле ввода в позиции x, y, при необходимости сдвигая текст к курсору
+func (t *TextInput) Draw() {
+	offset := t.offset()
+
+	for i := 0; i < t.width; i++ {
+		symbol := ' '
+		if i+offset < len(t.text) {
+			symbol = t.text[i+offset]
+		}
+
+		fColor, bColor := t.bColor, t.fColor
+		if t.focused {
+			bColor = CurrentTheme.Focused.Fg
+		}
+		if t.focused && i+offset == t.cursor {
+			fColor, bColor = t.fColor, t.bColor
+		}
+		termbox.SetCell(t.x+i, t.y, symbol, fColor, bColor)
+	}
+}
+
+// HandleEvent переносит курсор по нажатию ЛКМ на поле и редактирует текст, пока поле в фокусе
+// Фокус полю передает группа, в которую оно входит
+func (t *TextInput) HandleEvent(ev *termbox.Event) bool {
+	switch ev.Type {
+	case termbox.EventMouse:
+		if ev.Key != termbox.MouseLeft || ev.MouseX < t.x || ev.MouseX >= t.x+t.width || ev.MouseY != t.y {
+			return false
+		}
+		t.cursor = t.offset() + ev.MouseX - t.x
+		if t.cursor > len(t.text) {
+			t.cursor = len(t.text)
+		}
+		return true
+	case termbox.EventKey:
+		if !t.focused {
+			return false
+		}
+
+		switch ev.Key {
+		case termbox.KeyArrowLeft:
+			if t.cursor > 0 {
+				t.cursor--
+			}
+		case termbox.KeyArrowRight:
+			if t.cursor < len(t.text) {
+				t.cursor++
+			}
+		case termbox.KeyBackspace, termbox.KeyBackspace2:
+			if t.cursor > 0 {
+				t.text = append(t.text[:t.cursor-1], t.text[t.cursor:]...)
+				t.cursor--
+			}
+		case termbox.KeyEnter:
+			if t.action != nil {
+				t.action(t)
+			}
+		case termbox.KeySpace:
+			t.insert(' ')
+		default:
+			if ev.Ch == 0 {
+				return false
+			}
+			t.insert(ev.Ch)
+		}
+		return true
+	}
+
+	return false
+}
+
+// offset возвращает индекс первого видимого символа, при котором курсор остается в поле
+func (t *TextInput) offset() int {
+	if t.cursor >= t.width {
+		return t.cursor - t.width + 1
+	}
+
+	return 0
+}
+
+// insert вставляет символ в позицию курсора
+func (t *TextInput) insert(ch rune) {
+	t.text = append(t.text[:t.cursor], append([]rune{ch}, t.text[t.cursor:]...)...)
+	t.cursor++
+}
+
+// Text возвращает введенный текст
+func (t *TextInput) Text() string {
+	return string(t.text)
+}
+
+// SetText заменяет текст поля и переносит курсор в конец
+func (t *TextInput) SetText(text string) {
+	t.text = []rune(text)
+	t.cursor = len(t.text)
+}
+
+// SetFocus устанавливает или снимает фокус ввода
+func (t *TextInput) SetFocus(focused bool) {
+	t.focused = focused
+}
+
+// Size возвращает ширину и высоту поля ввода
+func (t *TextInput) Size() (int, int) {
+	return t.width, 1
+}
+
+// SetSize меняет ширину поля ввода
+func (t *TextInput) SetSize(width, height int) {
+	t.width = width
+}
+
+// Focused сообщает, находится ли поле ввода в фокусе
+func (t *TextInput) Focused() bool {
+	return t.focused
+}
+
+// SetPosition меняет позицию поля ввода на указанную
+func (t *TextInput) SetPosition(x, y int) {
+	t.x, t.y = x, y
+}
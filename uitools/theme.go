@@ -0,0 +1,259 @@
+package uitools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Style - пара атрибутов текста и фона
+type Style struct {
+	Fg termbox.Attribute
+	Bg termbox.Attribute
+}
+
+// Theme описывает цвета элементов интерфейса
+type Theme struct {
+	// OutputMode - режим вывода termbox, в котором заданы цвета темы
+	OutputMode termbox.OutputMode
+	// Normal - обычный текст и элементы управления
+	Normal Style
+	// Status - строка состояния и служебные отметки
+	Status Style
+	// Selected - выбранная строка таблицы или списка
+	Selected Style
+	// Focused - элемент управления в фокусе
+	Focused Style
+	// Warning - предупреждения
+	Warning Style
+	// States - цвета состояний, ключи задает приложение
+	States map[string]Style
+	// Palette - различимые цвета для раскраски однородных объектов, например сегментов разных процессов
+	Palette []Style
+}
+
+// CurrentTheme - тема, которой пользуются элементы управления
+var CurrentTheme = DefaultTheme()
+
+// SetTheme делает тему текущей и переключает режим вывода termbox
+func SetTheme(theme *Theme) {
+	CurrentTheme = theme
+	termbox.SetOutputMode(theme.OutputMode)
+}
+
+// DefaultTheme возвращает тему из 8 цветов: белый текст на синем фоне
+func DefaultTheme() *Theme {
+	return &Theme{
+		OutputMode: termbox.OutputNormal,
+		Normal:     Style{termbox.ColorWhite, termbox.ColorBlue},
+		Status:     Style{termbox.ColorBlue, termbox.ColorWhite},
+		Selected:   Style{termbox.ColorBlue, termbox.ColorWhite},
+		Focused:    Style{termbox.ColorYellow, termbox.ColorBlue},
+		Warning:    Style{termbox.ColorYellow, termbox.ColorBlue},
+		States: map[string]Style{
+			"execution": {termbox.ColorGreen | termbox.AttrBold, termbox.ColorBlue},
+			"readiness": {termbox.ColorWhite, termbox.ColorBlue},
+			"blocking":  {termbox.ColorRed | termbox.AttrBold, termbox.ColorBlue},
+			"suspended": {termbox.ColorCyan, termbox.ColorBlue},
+		},
+		Palette: []Style{
+			{termbox.ColorGreen, termbox.ColorBlue},
+			{termbox.ColorYellow, termbox.ColorBlue},
+			{termbox.ColorMagenta, termbox.ColorBlue},
+			{termbox.ColorCyan, termbox.ColorBlue},
+			{termbox.ColorRed, termbox.ColorBlue},
+			{termbox.ColorWhite | termbox.AttrBold, termbox.ColorBlue},
+		},
+	}
+}
+
+// Theme256 возвращает тему для терминалов с 256 цветами
+func Theme256() *Theme {
+	return &Theme{
+		OutputMode: termbox.Output256,
+		Normal:     Style{Color256(253), Color256(17)},
+		Status:     Style{Color256(17), Color256(250)},
+		Selected:   Style{Color256(17), Color256(153)},
+		Focused:    Style{Color256(220), Color256(17)},
+		Warning:    Style{Color256(208), Color256(17)},
+		States: map[string]Style{
+			"execution": {Color256(119), Color256(17)},
+			"readiness": {Color256(253), Color256(17)},
+			"blocking":  {Color256(203), Color256(17)},
+			"suspended": {Color256(245), Color256(17)},
+		},
+		Palette: []Style{
+			{Color256(46), Color256(17)},
+			{Color256(226), Color256(17)},
+			{Color256(201), Color256(17)},
+			{Color256(51), Color256(17)},
+			{Color256(208), Color256(17)},
+			{Color256(129), Color256(17)},
+			{Color256(118), Color256(17)},
+			{Color256(39), Color256(17)},
+			{Color256(214), Color256(17)},
+			{Color256(160), Color256(17)},
+			{Color256(87), Color256(17)},
+			{Color256(219), Color256(17)},
+		},
+	}
+}
+
+// MonochromeTheme возвращает тему без цветов, выделение передается яркостью, подчеркиванием и инверсией
+func MonochromeTheme() *Theme {
+	return &Theme{
+		OutputMode: termbox.OutputNormal,
+		Normal:     Style{termbox.ColorDefault, termbox.ColorDefault},
+		Status:     Style{termbox.ColorDefault | termbox.AttrReverse, termbox.ColorDefault},
+		Selected:   Style{termbox.ColorDefault | termbox.AttrReverse, termbox.ColorDefault},
+		Focused:    Style{termbox.ColorDefault | termbox.AttrBold, termbox.ColorDefault},
+		Warning:    Style{termbox.ColorDefault | termbox.AttrUnderline, termbox.ColorDefault},
+		States: map[string]Style{
+			"execution": {termbox.ColorDefault | termbox.AttrBold, termbox.ColorDefault},
+			"readiness": {termbox.ColorDefault, termbox.ColorDefault},
+			"blocking":  {termbox.ColorDefault | termbox.AttrUnderline, termbox.ColorDefault},
+			"suspended": {termbox.ColorDefault | termbox.AttrDim, termbox.ColorDefault},
+		},
+		Palette: []Style{
+			{termbox.ColorDefault, termbox.ColorDefault},
+			{termbox.ColorDefault | termbox.AttrBold, termbox.ColorDefault},
+			{termbox.ColorDefault | termbox.AttrUnderline, termbox.ColorDefault},
+			{termbox.ColorDefault | termbox.AttrDim, termbox.ColorDefault},
+		},
+	}
+}
+
+// ThemeByName возвращает встроенную тему по имени: default, 256 или mono
+func ThemeByName(name string) (*Theme, error) {
+	switch name {
+	case "", "default":
+		return DefaultTheme(), nil
+	case "256":
+		return Theme256(), nil
+	case "mono":
+		return MonochromeTheme(), nil
+	}
+
+	return nil, fmt.Errorf("неизвестная тема %q", name)
+}
+
+// State возвращает цвета состояния с заданным ключом или обычные цвета, если состояние не описано
+func (t *Theme) State(key string) Style {
+	if style, ok := t.States[key]; ok {
+		return style
+	}
+
+	return t.Normal
+}
+
+// PaletteStyle возвращает цвет палитры с заданным номером, номера больше размера палитры повторяют ее по кругу
+func (t *Theme) PaletteStyle(index int) Style {
+	if len(t.Palette) == 0 {
+		return t.Normal
+	}
+	if index < 0 {
+		index = -index
+	}
+
+	return t.Palette[index%len(t.Palette)]
+}
+
+// Set переопределяет цвета элемента темы строкой вида "текст/фон", например "yellow+bold/blue" или "220/17"
+// Элементы: normal, status, selected, focused, warning, остальные ключи относятся к состояниям
+func (t *Theme) Set(key, spec string) error {
+	style, err := ParseStyle(spec)
+	if err != nil {
+		return err
+	}
+
+	switch key {
+	case "normal":
+		t.Normal = style
+	case "status":
+		t.Status = style
+	case "selected":
+		t.Selected = style
+	case "focused":
+		t.Focused = style
+	case "warning":
+		t.Warning = style
+	default:
+		if t.States == nil {
+			t.States = map[string]Style{}
+		}
+		t.States[key] = style
+	}
+
+	return nil
+}
+
+// Color256 переводит номер цвета палитры из 256 цветов в атрибут termbox
+func Color256(index int) termbox.Attribute {
+	return termbox.Attribute(index + 1)
+}
+
+// colorNames - имена цветов палитры из 8 цветов
+var colorNames = map[string]termbox.Attribute{
+	"default": termbox.ColorDefault,
+	"black":   termbox.ColorBlack,
+	"red":     termbox.ColorRed,
+	"green":   termbox.ColorGreen,
+	"yellow":  termbox.ColorYellow,
+	"blue":    termbox.ColorBlue,
+	"magenta": termbox.ColorMagenta,
+	"cyan":    termbox.ColorCyan,
+	"white":   termbox.ColorWhite,
+}
+
+// attributeNames - имена дополнительных атрибутов текста
+var attributeNames = map[string]termbox.Attribute{
+	"bold":      termbox.AttrBold,
+	"underline": termbox.AttrUnderline,
+	"reverse":   termbox.AttrReverse,
+}
+
+// ParseStyle разбирает строку вида "текст/фон", где цвет - имя или номер палитры из 256 цветов,
+// к которому через "+" могут добавляться атрибуты bold, underline, reverse
+func ParseStyle(spec string) (Style, error) {
+	parts := strings.Split(spec, "/")
+	if len(parts) != 2 {
+		return Style{}, fmt.Errorf("ожидается стиль вида текст/фон: %q", spec)
+	}
+
+	fg, err := parseAttribute(parts[0])
+	if err != nil {
+		return Style{}, err
+	}
+	bg, err := parseAttribute(parts[1])
+	if err != nil {
+		return Style{}, err
+	}
+
+	return Style{fg, bg}, nil
+}
+
+// parseAttribute разбирает цвет с необязательными атрибутами
+func parseAttribute(spec string) (termbox.Attribute, error) {
+	names := strings.Split(strings.TrimSpace(spec), "+")
+
+	var attr termbox.Attribute
+	if color, ok := colorNames[names[0]]; ok {
+		attr = color
+	} else if index, err := strconv.Atoi(names[0]); err == nil && index >= 0 && index < 256 {
+		attr = Color256(index)
+	} else {
+		return 0, fmt.Errorf("неизвестный цвет %q", names[0])
+	}
+
+	for _, name := range names[1:] {
+		a, ok := attributeNames[name]
+		if !ok {
+			return 0, fmt.Errorf("неизвестный атрибут %q", name)
+		}
+		attr |= a
+	}
+
+	return attr, nil
+}
@@ -2,10 +2,19 @@ package uitools
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nsf/termbox-go"
 )
 
+// Widget - общий интерфейс элементов управления
+type Widget interface {
+	// Draw отображает элемент управления
+	Draw()
+	// HandleEvent обрабатывает событие и сообщает, было ли оно поглощено элементом
+	HandleEvent(ev *termbox.Event) bool
+}
+
 // Print печатает текст в заданной позиции экрана
 func Print(x, y int, fColor, bColor termbox.Attribute, caption string) {
 	symbols := []rune(caption)
@@ -19,3 +28,35 @@ func Print(x, y int, fColor, bColor termbox.Attribute, caption string) {
 func Printf(x, y int, fColor, bColor termbox.Attribute, caption string, a ...interface{}) {
 	Print(x, y, fColor, bColor, fmt.Sprintf(caption, a...))
 }
+
+// fitString обрезает или дополняет пробелами строку до заданной ширины
+func fitString(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// alignRight выравнивает строку вправо в пределах заданной ширины
+func alignRight(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+
+	return strings.Repeat(" ", width-len(runes)) + s
+}
+
+// clamp ограничивает значение отрезком [low, high], при high < low возвращает low
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+
+	return value
+}